@@ -0,0 +1,180 @@
+package toposort
+
+import (
+	"context"
+	"math/bits"
+)
+
+// Width returns the size of the largest set of keys not depending on each
+// other, directly or not, which is the maximum number of keys that can be
+// processed in parallel, and so the maximum useful number of workers.
+//
+// By Dilworth's theorem, that's the number of keys minus the size of a
+// maximum matching between keys and their transitive dependents. It costs
+// up to the cube of the number of keys in time, and holds the transitive
+// closure of the graph in V²/8 bytes for V keys, like TransitiveClosure, so
+// Stats().MaxLayer, a lower bound of the width, may be preferred on large
+// graphs.
+func (g *Graph[K]) Width() int {
+	sets, _ := g.closure(context.Background())
+	n := len(sets)
+
+	match := make([]int, n) // position matched to each position, or -1
+	for i := range match {
+		match[i] = -1
+	}
+
+	var augment func(i int, seen []bool) bool
+
+	augment = func(i int, seen []bool) bool {
+		for w, bits := range sets[i] {
+			for b := 0; bits != 0; b, bits = b+1, bits>>1 {
+				j := w*64 + b
+				if bits&1 == 0 || seen[j] {
+					continue
+				}
+				seen[j] = true
+				if match[j] < 0 || augment(match[j], seen) {
+					match[j] = i
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	matched := 0
+	for i := 0; i < n; i++ {
+		if augment(i, make([]bool, n)) {
+			matched++
+		}
+	}
+
+	return n - matched
+}
+
+// MaximalAntichains enumerates the maximal sets of keys not depending on
+// each other, directly or not, to which no other key can be added, stopping
+// after limit sets unless limit is less than 1. Keys of each set are in
+// topological order, and any order of them is valid, like a batch of
+// migrations that can be applied in any order.
+//
+// The number of maximal antichains may grow exponentially with the size of
+// the graph, and the transitive closure and independence of the keys are held
+// in V²/4 bytes for V keys, so this is only suited to small graphs.
+func (g *Graph[K]) MaximalAntichains(limit int) [][]K {
+	sets, _ := g.closure(context.Background())
+	n := len(sets)
+	words := (n + 63) / 64
+
+	// keys independent of each key, as bit sets of positions
+	independent := make([][]uint64, n)
+	for i := range independent {
+		independent[i] = make([]uint64, words)
+		for j := 0; j < n; j++ {
+			independent[i][j/64] |= 1 << (j % 64)
+		}
+		independent[i][i/64] &^= 1 << (i % 64)
+		for w, bits := range sets[i] {
+			independent[i][w] &^= bits
+		}
+	}
+	for i := range sets {
+		forEachBit(sets[i], func(j int) bool {
+			independent[j][i/64] &^= 1 << (i % 64)
+			return true
+		})
+	}
+
+	antichains := [][]K{}
+
+	// maximal cliques of the independence graph, with the Bron-Kerbosch
+	// algorithm pivoting on the key with the most candidates
+	var visit func(r []int, p, x []uint64) bool
+
+	visit = func(r []int, p, x []uint64) bool {
+		if isEmpty(p) {
+			if isEmpty(x) {
+				vs := make([]int, len(r))
+				for i, j := range r {
+					vs[i] = g.sorted[j]
+				}
+				antichains = append(antichains, g.keys(vs))
+				return limit > 0 && len(antichains) >= limit
+			}
+			return false
+		}
+
+		pivot, most := -1, -1
+		for _, s := range [][]uint64{p, x} {
+			forEachBit(s, func(u int) bool {
+				if c := countAnd(p, independent[u]); c > most {
+					pivot, most = u, c
+				}
+				return true
+			})
+		}
+
+		candidates := append([]uint64{}, p...)
+		for w := range candidates {
+			candidates[w] &^= independent[pivot][w]
+		}
+
+		stop := false
+		forEachBit(candidates, func(v int) bool {
+			np := make([]uint64, words)
+			nx := make([]uint64, words)
+			for w := range np {
+				np[w] = p[w] & independent[v][w]
+				nx[w] = x[w] & independent[v][w]
+			}
+			if stop = visit(append(r, v), np, nx); stop {
+				return false
+			}
+			p[v/64] &^= 1 << (v % 64)
+			x[v/64] |= 1 << (v % 64)
+			return true
+		})
+		return stop
+	}
+
+	p := make([]uint64, words)
+	for j := 0; j < n; j++ {
+		p[j/64] |= 1 << (j % 64)
+	}
+	if n > 0 {
+		visit([]int{}, p, make([]uint64, words))
+	}
+
+	return antichains
+}
+
+// forEachBit calls fn with the index of every bit set in s, in increasing
+// order, until fn returns false.
+func forEachBit(s []uint64, fn func(i int) bool) {
+	for w, bits := range s {
+		for b := 0; bits != 0; b, bits = b+1, bits>>1 {
+			if bits&1 != 0 && !fn(w*64+b) {
+				return
+			}
+		}
+	}
+}
+
+// isEmpty reports whether no bit is set in s.
+func isEmpty(s []uint64) bool {
+	for _, bits := range s {
+		if bits != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// countAnd returns the number of bits set in both a and b.
+func countAnd(a, b []uint64) (n int) {
+	for w := range a {
+		n += bits.OnesCount64(a[w] & b[w])
+	}
+	return
+}
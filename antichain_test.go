@@ -0,0 +1,87 @@
+package toposort_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphWidth(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		dot   string
+		width int
+	}{
+		{
+			desc:  "chain",
+			dot:   `digraph { a -> b -> c }`,
+			width: 1,
+		},
+		{
+			desc:  "diamond",
+			dot:   `digraph { a -> b -> d; a -> c -> d }`,
+			width: 2,
+		},
+		{
+			// the largest layer has 2 keys, but x, c and y are
+			// independent
+			desc:  "wider than layers",
+			dot:   `digraph { a -> b -> c -> d; a -> x; b -> y }`,
+			width: 3,
+		},
+		{
+			desc:  "empty",
+			dot:   `digraph {}`,
+			width: 0,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			g, err := toposort.ParseDOT(strings.NewReader(tt.dot), toposort.WithAllowMultipleRoots())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if width := g.Width(); width != tt.width {
+				t.Fatalf("expected width %d != %d", tt.width, width)
+			}
+		})
+	}
+}
+
+func TestGraphMaximalAntichains(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> c -> d;
+	a -> x;
+	b -> y;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	antichains := g.MaximalAntichains(0)
+	joined := []string{}
+	for _, antichain := range antichains {
+		for i, id := range antichain {
+			for _, other := range antichain[i+1:] {
+				if g.Reachable(id, other) || g.Reachable(other, id) {
+					t.Fatalf("expected %s and %s to be independent in %v", id, other, antichain)
+				}
+			}
+		}
+		keys := append([]string{}, antichain...)
+		sort.Strings(keys)
+		joined = append(joined, strings.Join(keys, ""))
+	}
+	expected := []string{"a", "bx", "cxy", "dxy"}
+	if !sameKeys(joined, expected) {
+		t.Fatalf("expected antichains %v != %v", expected, joined)
+	}
+
+	if antichains := g.MaximalAntichains(2); len(antichains) != 2 {
+		t.Fatalf("expected 2 antichains, got %v", antichains)
+	}
+}
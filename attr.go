@@ -0,0 +1,114 @@
+package toposort
+
+import "fmt"
+
+// SetAttr attaches value to id under key, so that arbitrary payloads can be
+// carried along with the keys of the graph. It returns an error wrapping
+// ErrNotFound if id is not in the graph.
+//
+// Attributes are copied to the graphs derived from g, like subgraphs.
+// SetAttr must not be called concurrently with other methods of g.
+func (g *Graph[K]) SetAttr(id K, key string, value any) error {
+	v, ok := g.lookup(id)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNotFound, id)
+	}
+	if g.attrs == nil {
+		g.attrs = make([]map[string]any, len(g.ids))
+	}
+	if g.attrs[v] == nil {
+		g.attrs[v] = make(map[string]any)
+	}
+	g.attrs[v][key] = value
+
+	return nil
+}
+
+// Attr returns the value attached to id under key, and whether there's one.
+func (g *Graph[K]) Attr(id K, key string) (any, bool) {
+	v, ok := g.lookup(id)
+	if !ok || g.attrs == nil {
+		return nil, false
+	}
+	value, ok := g.attrs[v][key]
+	return value, ok
+}
+
+// Attrs returns a copy of the attributes attached to id, or nil if there are
+// none.
+func (g *Graph[K]) Attrs(id K) map[string]any {
+	v, ok := g.lookup(id)
+	if !ok || g.attrs == nil || len(g.attrs[v]) == 0 {
+		return nil
+	}
+	attrs := make(map[string]any, len(g.attrs[v]))
+	for k, value := range g.attrs[v] {
+		attrs[k] = value
+	}
+	return attrs
+}
+
+// SetWeight sets the weight of id, like the duration of a build step, which
+// is 1 by default. Weights are expected to be non-negative. It returns an
+// error wrapping ErrNotFound if id is not in the graph.
+//
+// Weights are copied to the graphs derived from g, like attributes.
+// SetWeight must not be called concurrently with other methods of g.
+func (g *Graph[K]) SetWeight(id K, w float64) error {
+	v, ok := g.lookup(id)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNotFound, id)
+	}
+	if g.weights == nil {
+		g.weights = make([]float64, len(g.ids))
+		for i := range g.weights {
+			g.weights[i] = 1
+		}
+	}
+	g.weights[v] = w
+
+	return nil
+}
+
+// Weight returns the weight of id, or 0 if id is not in the graph.
+func (g *Graph[K]) Weight(id K) float64 {
+	v, ok := g.lookup(id)
+	if !ok {
+		return 0
+	}
+	return g.weight(v)
+}
+
+// weight returns the weight of v.
+func (g *Graph[K]) weight(v int) float64 {
+	if g.weights == nil {
+		return 1
+	}
+	return g.weights[v]
+}
+
+// copyAttrs copies the attributes, weights and spellings of the keys of g to
+// the same keys in ng, and the labels of the edges of g that ng has.
+func (g *Graph[K]) copyAttrs(ng *Graph[K]) {
+	for id, original := range g.originals {
+		if _, ok := ng.vertex[id]; ok {
+			ng.remember(original, id)
+		}
+	}
+	for e, label := range g.labels {
+		ng.SetEdgeLabel(e[0], e[1], label)
+	}
+	if g.weights != nil {
+		for v, w := range g.weights {
+			ng.SetWeight(g.ids[v], w)
+		}
+	}
+	if g.attrs == nil {
+		return
+	}
+	for v, attrs := range g.attrs {
+		for key, value := range attrs {
+			ng.SetAttr(g.ids[v], key, value)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphAttrs(t *testing.T) {
+	g := newExampleGraph(t)
+
+	type employee struct{ title string }
+
+	if err := g.SetAttr("Nick", "employee", employee{"Manager"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetAttr("Nick", "floor", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetAttr("Ruby", "floor", 1); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+
+	if v, ok := g.Attr("Nick", "employee"); !ok || v.(employee).title != "Manager" {
+		t.Fatalf("unexpected attribute %v", v)
+	}
+	if _, ok := g.Attr("Nick", "desk"); ok {
+		t.Fatal("unexpected desk attribute")
+	}
+	if _, ok := g.Attr("Jonas", "floor"); ok {
+		t.Fatal("unexpected floor attribute")
+	}
+
+	expected := map[string]any{"employee": employee{"Manager"}, "floor": 3}
+	if attrs := g.Attrs("Nick"); !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("expected attributes %v != %v", expected, attrs)
+	}
+	if attrs := g.Attrs("Jonas"); attrs != nil {
+		t.Fatalf("unexpected attributes %v", attrs)
+	}
+
+	sub, err := g.Subgraph("Sophie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs := sub.Attrs("Nick"); !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("expected subgraph attributes %v != %v", expected, attrs)
+	}
+}
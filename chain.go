@@ -0,0 +1,33 @@
+package toposort
+
+import "fmt"
+
+// Chain returns the keys of a graph made of a single chain, from the root to
+// the leaf, each key depending on the previous one. It returns an error
+// wrapping ErrNotChain, naming the offending key, if a key has more than one
+// dependency or dependent, or if the graph has more than one root.
+func (g *Graph[K]) Chain() ([]K, error) {
+	if err := g.chainError(); err != nil {
+		return nil, err
+	}
+	return g.keys(g.sorted), nil
+}
+
+// chainError returns why g is not a single chain, or nil if it's one.
+func (g *Graph[K]) chainError() error {
+	for _, v := range g.sorted {
+		if len(g.befores[v]) > 1 {
+			return fmt.Errorf("%w: %v depends on %d keys %v", ErrNotChain, g.spelling(g.ids[v]), len(g.befores[v]), g.spellings(g.befores[v]))
+		}
+		if len(g.afters[v]) > 1 {
+			return fmt.Errorf("%w: %v has %d dependents %v", ErrNotChain, g.spelling(g.ids[v]), len(g.afters[v]), g.spellings(g.afters[v]))
+		}
+	}
+	if roots := g.Roots(); len(roots) > 1 {
+		for i, id := range roots {
+			roots[i] = g.spelling(id)
+		}
+		return fmt.Errorf("%w: %d roots %v", ErrNotChain, len(roots), roots)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphChain(t *testing.T) {
+	chain, err := newExampleGraph(t).Chain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Jonas", "Sophie", "Nick", "Barbara"}; !reflect.DeepEqual(chain, expected) {
+		t.Fatalf("expected chain %v != %v", expected, chain)
+	}
+
+	testCases := []struct {
+		desc     string
+		dot      string
+		expected string
+	}{
+		{
+			desc:     "fork",
+			dot:      `digraph { a -> b; a -> c }`,
+			expected: "not a chain: a has 2 dependents [b c]",
+		},
+		{
+			desc:     "join",
+			dot:      `digraph { a -> c; b -> c }`,
+			expected: "not a chain: c depends on 2 keys [a b]",
+		},
+		{
+			desc:     "forest",
+			dot:      `digraph { a -> b; c -> d }`,
+			expected: "not a chain: 2 roots",
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			g, err := toposort.ParseDOT(strings.NewReader(tt.dot), toposort.WithAllowMultipleRoots())
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = g.Chain()
+			if !errors.Is(err, toposort.ErrNotChain) || !strings.HasPrefix(err.Error(), tt.expected) {
+				t.Fatalf("expected error %q != %v", tt.expected, err)
+			}
+			_, err = toposort.ParseDOT(strings.NewReader(tt.dot), toposort.WithAllowMultipleRoots(), toposort.WithStrictChain())
+			if !errors.Is(err, toposort.ErrNotChain) {
+				t.Fatalf("expected error %v != %v", toposort.ErrNotChain, err)
+			}
+		})
+	}
+}
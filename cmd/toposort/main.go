@@ -0,0 +1,202 @@
+// Command toposort reads edges from standard input and prints the keys in
+// topological order, the cycles found, or a rendering of the graph.
+//
+// Usage:
+//
+//	toposort [-in format] [-out output] [-lex] [-single-root] [-tsort] [file]
+//
+// Edges are read from file if given, and from standard input otherwise, in
+// one of the input formats:
+//
+//	pairs   white space separated pairs, like "a b", where b depends on a
+//	tsort   pairs as read by tsort(1), which may span lines, "a a" declaring a
+//	arrows  arrows, like "a -> b -> c", where each key depends on the previous one
+//	csv     records of two columns, parent and child
+//	json    an object mapping each key to the list of keys it depends on
+//
+// The outputs are order, which prints a key per line, cycles, which prints
+// the path of each cycle found, and the dot, mermaid, plantuml, d2 and graphml
+// renderings of the graph.
+//
+// Errors, like the cycles keeping the keys from being sorted, are printed to
+// standard error, one per line, and make toposort exit with status 1.
+//
+// With -tsort, toposort is a drop-in replacement for tsort(1): it reads the
+// tsort input format, and when the input contains cycles, it reports them
+// and still prints an order, ignoring the edges that close them, before
+// exiting with status 1.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/onur1/toposort"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// outputs maps the outputs rendering the graph to their export formats.
+var outputs = map[string]toposort.Format{
+	"dot":      toposort.FormatDOT,
+	"mermaid":  toposort.FormatMermaid,
+	"plantuml": toposort.FormatPlantUML,
+	"d2":       toposort.FormatD2,
+	"graphml":  toposort.FormatGraphML,
+}
+
+// inputs maps the input formats to the functions reading them.
+var inputs = map[string]func(r io.Reader, opts []toposort.Option) (*toposort.Graph[string], error){
+	"pairs": func(r io.Reader, opts []toposort.Option) (*toposort.Graph[string], error) {
+		return toposort.ReadEdges(r, toposort.FormatPairs, opts...)
+	},
+	"tsort": func(r io.Reader, opts []toposort.Option) (*toposort.Graph[string], error) {
+		return toposort.ReadEdges(r, toposort.FormatTsort, opts...)
+	},
+	"arrows": func(r io.Reader, opts []toposort.Option) (*toposort.Graph[string], error) {
+		return toposort.ReadEdges(r, toposort.FormatArrows, opts...)
+	},
+	"csv": func(r io.Reader, opts []toposort.Option) (*toposort.Graph[string], error) {
+		return toposort.NewGraphFromCSV(r, 1, 0, append(opts, toposort.WithTrimSpace())...)
+	},
+	"json": func(r io.Reader, opts []toposort.Option) (*toposort.Graph[string], error) {
+		return toposort.ReadDependencies(r, opts...)
+	},
+}
+
+// run runs the command with the given arguments and streams, returning its
+// exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("toposort", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	in := fs.String("in", "pairs", "input `format`: pairs, tsort, arrows, csv or json")
+	out := fs.String("out", "order", "`output`: order, cycles, dot, mermaid, plantuml, d2 or graphml")
+	lex := fs.Bool("lex", false, "sort keys lexicographically when the order is free")
+	singleRoot := fs.Bool("single-root", false, "reject graphs with more than one root")
+	tsort := fs.Bool("tsort", false, "behave like tsort(1), printing an order despite cycles")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(stderr, "toposort: unexpected argument %q\n", fs.Arg(1))
+		return 2
+	}
+	if fs.NArg() == 1 && fs.Arg(0) != "-" {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "toposort: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		stdin = f
+	}
+	if *tsort {
+		*in, *out = "tsort", "order"
+	}
+
+	// the input is read again to print an order despite cycles
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "toposort: %v\n", err)
+		return 1
+	}
+
+	opts := []toposort.Option{}
+	if !*singleRoot {
+		opts = append(opts, toposort.WithAllowMultipleRoots())
+	}
+	if *lex {
+		opts = append(opts, toposort.WithLexicographicOrder())
+	}
+
+	read, ok := inputs[*in]
+	if !ok {
+		fmt.Fprintf(stderr, "toposort: unknown input format %q\n", *in)
+		return 2
+	}
+	g, err := read(bytes.NewReader(data), opts)
+
+	if *out == "cycles" {
+		return printCycles(err, stdout, stderr)
+	}
+	format, ok := outputs[*out]
+	if !ok && *out != "order" {
+		fmt.Fprintf(stderr, "toposort: unknown output %q\n", *out)
+		return 2
+	}
+	status := 0
+	if err != nil {
+		printErrors(err, stderr)
+		if !*tsort || !errors.Is(err, toposort.ErrCircular) {
+			return 1
+		}
+		status = 1
+		if g, err = read(bytes.NewReader(data), append(opts, toposort.WithCyclePolicy(toposort.CycleIgnore))); err != nil {
+			return 1
+		}
+	}
+
+	if *out == "order" {
+		var b strings.Builder
+		for _, id := range g.SortedIDs() {
+			b.WriteString(id)
+			b.WriteByte('\n')
+		}
+		_, err = io.WriteString(stdout, b.String())
+	} else {
+		err = g.Export(stdout, format)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "toposort: %v\n", err)
+		return 1
+	}
+
+	return status
+}
+
+// printCycles prints the path of each cycle reported by err, and any other
+// error. Finding cycles is not a failure.
+func printCycles(err error, stdout, stderr io.Writer) int {
+	status := 0
+	for _, e := range flatten(err) {
+		var ce *toposort.CycleError[string]
+		var se *toposort.SelfReferenceError[string]
+		switch {
+		case errors.As(e, &ce):
+			fmt.Fprintln(stdout, strings.Join(ce.Path, " -> "))
+		case errors.As(e, &se):
+			fmt.Fprintf(stdout, "%s -> %[1]s\n", se.ID)
+		case errors.Is(e, toposort.ErrMultipleRoots):
+		default:
+			fmt.Fprintf(stderr, "toposort: %v\n", e)
+			status = 1
+		}
+	}
+	return status
+}
+
+// printErrors prints the errors reported by err, one per line.
+func printErrors(err error, stderr io.Writer) {
+	for _, e := range flatten(err) {
+		fmt.Fprintf(stderr, "toposort: %v\n", e)
+	}
+}
+
+// flatten returns the errors of a MultiError, or err alone.
+func flatten(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var m toposort.MultiError
+	if errors.As(err, &m) {
+		return m.Errors()
+	}
+	return []error{err}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		args   []string
+		stdin  string
+		stdout string
+		stderr string
+		status int
+	}{
+		{
+			desc:   "pairs",
+			stdin:  "Sophie Nick\nJonas Sophie\n",
+			stdout: "Jonas\nSophie\nNick\n",
+		},
+		{
+			desc:   "arrows",
+			args:   []string{"-in", "arrows", "-lex"},
+			stdin:  "a -> c\nb -> c\n",
+			stdout: "a\nb\nc\n",
+		},
+		{
+			desc:   "csv",
+			args:   []string{"-in", "csv"},
+			stdin:  "Jonas, Sophie\nSophie, Nick\n",
+			stdout: "Jonas\nSophie\nNick\n",
+		},
+		{
+			desc:   "json",
+			args:   []string{"-in", "json"},
+			stdin:  `{"Nick": ["Sophie"], "Sophie": []}`,
+			stdout: "Sophie\nNick\n",
+		},
+		{
+			desc:   "cycle",
+			stdin:  "a b\nb a\n",
+			stderr: "toposort: cyclic: [a b a]\n",
+			status: 1,
+		},
+		{
+			desc:   "cycles",
+			args:   []string{"-out", "cycles"},
+			stdin:  "a b\nb a\nc c\n",
+			stdout: "a -> b -> a\nc -> c\n",
+		},
+		{
+			desc:   "single root",
+			args:   []string{"-single-root", "-lex"},
+			stdin:  "a b\nc d\n",
+			stderr: "toposort: multiple roots: [a c]\n",
+			status: 1,
+		},
+		{
+			desc:   "d2",
+			args:   []string{"-out", "d2"},
+			stdin:  "a b\n",
+			stdout: "\"a\"\n\"b\"\n\"a\" -> \"b\"\n",
+		},
+		{
+			desc:   "tsort",
+			args:   []string{"-tsort"},
+			stdin:  "a b b\nc c c\n",
+			stdout: "a\nb\nc\n",
+		},
+		{
+			desc:   "tsort cycle",
+			args:   []string{"-tsort"},
+			stdin:  "a b b a b c",
+			stdout: "a\nb\nc\n",
+			stderr: "toposort: cyclic: [a b a]\n",
+			status: 1,
+		},
+		{
+			desc:   "unknown output",
+			args:   []string{"-out", "svg"},
+			stderr: "toposort: unknown output \"svg\"\n",
+			status: 2,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.status {
+				t.Fatalf("expected status %d != %d (%s)", tt.status, status, stderr.String())
+			}
+			if stdout.String() != tt.stdout {
+				t.Fatalf("expected output %q != %q", tt.stdout, stdout.String())
+			}
+			if stderr.String() != tt.stderr {
+				t.Fatalf("expected errors %q != %q", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
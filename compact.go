@@ -0,0 +1,361 @@
+package toposort
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CompactGraph is a read-only, memory efficient copy of a graph, for large
+// graphs that are only queried once built.
+//
+// Vertices are numbered in topological order, and the edges are stored in
+// compressed sparse row form, two flat arrays of 32-bit vertices indexed by
+// offsets, instead of a slice per vertex. A compact graph is safe for
+// concurrent use, and holds at most 2^31-1 keys and edges.
+type CompactGraph[K comparable] struct {
+	ids     []K       // keys in topological order
+	vertex  map[K]int // vertices by key
+	offsets []int32   // offsets of the edges from each vertex in afters
+	afters  []int32   // vertices depending on each vertex
+	inOff   []int32   // offsets of the edges to each vertex in befores
+	befores []int32   // vertices each vertex depends on
+}
+
+// ErrTooLarge is raised when a graph has too many keys or edges for a
+// compact graph.
+var ErrTooLarge = errors.New("too large for a compact graph")
+
+// Compact returns a compact copy of g, which can be dropped afterwards.
+// Attributes, weights and labels aren't copied. It returns an error wrapping
+// ErrTooLarge if g has more than 2^31-1 keys or edges.
+//
+// NewCompactGraph builds a compact graph without building a Graph first.
+func (g *Graph[K]) Compact() (*CompactGraph[K], error) {
+	n := len(g.sorted)
+	m := 0
+	for _, v := range g.sorted {
+		m += len(g.afters[v])
+	}
+	if err := checkCompactSize(n, m); err != nil {
+		return nil, err
+	}
+
+	c := &CompactGraph[K]{
+		ids:     g.keys(g.sorted),
+		vertex:  make(map[K]int, n),
+		offsets: make([]int32, n+1),
+		afters:  make([]int32, 0, m),
+		inOff:   make([]int32, n+1),
+		befores: make([]int32, 0, m),
+	}
+	for i, id := range c.ids {
+		c.vertex[id] = i
+	}
+	for i, v := range g.sorted {
+		c.afters = appendPositions(c.afters, g.afters[v], g.position)
+		c.offsets[i+1] = int32(len(c.afters))
+		c.befores = appendPositions(c.befores, g.befores[v], g.position)
+		c.inOff[i+1] = int32(len(c.befores))
+	}
+
+	return c, nil
+}
+
+// NewCompactGraph builds a compact graph from the given keys and edges, each
+// edge pointing from a key to a key depending on it, as returned by
+// Graph.Edges. Keys only need to be given if they have no edges, and
+// duplicate edges are dropped.
+//
+// The graph is sorted with Kahn's algorithm straight into the compact form,
+// without building a Graph, so that large graphs only take a fraction of
+// the memory. Unlike NewGraph, it takes no options, doesn't check the graph
+// for multiple roots, and returns an error wrapping ErrCircular without the
+// cycles if there are any. It returns an error wrapping ErrTooLarge if there
+// are more than 2^31-1 keys or edges.
+func NewCompactGraph[K comparable](ids []K, edges [][2]K) (*CompactGraph[K], error) {
+	if err := checkCompactSize(0, len(edges)); err != nil {
+		return nil, err
+	}
+
+	// vertices are numbered in order of appearance first
+	c := &CompactGraph[K]{vertex: make(map[K]int, len(ids))}
+	intern := func(id K) int32 {
+		v, ok := c.vertex[id]
+		if !ok {
+			v = len(c.ids)
+			c.vertex[id] = v
+			c.ids = append(c.ids, id)
+		}
+		return int32(v)
+	}
+	for _, id := range ids {
+		intern(id)
+	}
+	pairs := make([][2]int32, len(edges))
+	for i, e := range edges {
+		if e[0] == e[1] {
+			return nil, &SelfReferenceError[K]{ID: e[0]}
+		}
+		pairs[i] = [2]int32{intern(e[0]), intern(e[1])}
+	}
+	// vertices past 2^31-1 wrapped around, but they're rejected here
+	if err := checkCompactSize(len(c.ids), len(pairs)); err != nil {
+		return nil, err
+	}
+
+	n := len(c.ids)
+	offsets, afters := csr(n, pairs, false)
+	pairs = nil
+
+	// sort, then number vertices in topological order
+	indegree := make([]int32, n)
+	for _, after := range afters {
+		indegree[after]++
+	}
+	order := make([]int32, 0, n)
+	for v := range indegree {
+		if indegree[v] == 0 {
+			order = append(order, int32(v))
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		v := order[i]
+		for _, after := range afters[offsets[v]:offsets[v+1]] {
+			if indegree[after]--; indegree[after] == 0 {
+				order = append(order, after)
+			}
+		}
+	}
+	if len(order) < n {
+		return nil, fmt.Errorf("%w: %d keys are on a cycle or depend on one", ErrCircular, n-len(order))
+	}
+
+	position := indegree // reused, as it's all zeros now
+	sorted := make([]K, n)
+	for i, v := range order {
+		position[v] = int32(i)
+		sorted[i] = c.ids[v]
+		c.vertex[sorted[i]] = i
+	}
+	c.ids = sorted
+
+	pairs = make([][2]int32, 0, len(afters))
+	for v := 0; v < n; v++ {
+		for _, after := range afters[offsets[v]:offsets[v+1]] {
+			pairs = append(pairs, [2]int32{position[v], position[after]})
+		}
+	}
+	offsets, afters = nil, nil
+	c.offsets, c.afters = csr(n, pairs, false)
+	c.inOff, c.befores = csr(n, pairs, true)
+
+	return c, nil
+}
+
+// csr returns the edges of n vertices in compressed sparse row form, the
+// edges from each vertex, or to each vertex if reversed, sorted and without
+// duplicates.
+func csr(n int, pairs [][2]int32, reversed bool) (offsets, edges []int32) {
+	from, to := 0, 1
+	if reversed {
+		from, to = 1, 0
+	}
+
+	offsets = make([]int32, n+1)
+	for _, p := range pairs {
+		offsets[p[from]+1]++
+	}
+	for v := 0; v < n; v++ {
+		offsets[v+1] += offsets[v]
+	}
+	edges = make([]int32, len(pairs))
+	next := append([]int32{}, offsets[:n]...)
+	for _, p := range pairs {
+		edges[next[p[from]]] = p[to]
+		next[p[from]]++
+	}
+
+	// rows are sorted and compacted in place
+	m := int32(0)
+	for v := 0; v < n; v++ {
+		row := edges[offsets[v]:offsets[v+1]]
+		sort.Slice(row, func(i, j int) bool { return row[i] < row[j] })
+		offsets[v] = m
+		for _, w := range row {
+			if m == offsets[v] || edges[m-1] != w {
+				edges[m] = w
+				m++
+			}
+		}
+	}
+	offsets[n] = m
+
+	return offsets, edges[:m:m]
+}
+
+// checkCompactSize returns an error wrapping ErrTooLarge if n keys or m edges
+// don't fit the 32-bit vertices and offsets of a compact graph.
+func checkCompactSize(n, m int) error {
+	if n > math.MaxInt32 || m > math.MaxInt32 {
+		return fmt.Errorf("%w: %d keys and %d edges", ErrTooLarge, n, m)
+	}
+	return nil
+}
+
+// appendPositions appends the sorted positions of vs to dst.
+func appendPositions(dst []int32, vs []int, position []int) []int32 {
+	start := len(dst)
+	for _, v := range vs {
+		dst = append(dst, int32(position[v]))
+	}
+	sort.Slice(dst[start:], func(i, j int) bool { return dst[start+i] < dst[start+j] })
+	return dst
+}
+
+// SortedIDs returns the keys of the graph in topological order. The returned
+// slice is shared, and must not be modified.
+func (c *CompactGraph[K]) SortedIDs() []K {
+	return c.ids
+}
+
+// Index returns the position of id in the topological order, or -1 if id is
+// not in the graph.
+func (c *CompactGraph[K]) Index(id K) int {
+	if v, ok := c.vertex[id]; ok {
+		return v
+	}
+	return -1
+}
+
+// InDegree returns the number of keys id directly depends on, or 0 if id is
+// not in the graph.
+func (c *CompactGraph[K]) InDegree(id K) int {
+	if v, ok := c.vertex[id]; ok {
+		return int(c.inOff[v+1] - c.inOff[v])
+	}
+	return 0
+}
+
+// OutDegree returns the number of keys directly depending on id, or 0 if id
+// is not in the graph.
+func (c *CompactGraph[K]) OutDegree(id K) int {
+	if v, ok := c.vertex[id]; ok {
+		return int(c.offsets[v+1] - c.offsets[v])
+	}
+	return 0
+}
+
+// Edges returns the edges of the graph as [from, to] pairs, where to depends
+// on from, ordered by the topological order of from, then of to.
+func (c *CompactGraph[K]) Edges() [][2]K {
+	edges := make([][2]K, 0, len(c.afters))
+	for v := range c.ids {
+		for _, after := range c.afters[c.offsets[v]:c.offsets[v+1]] {
+			edges = append(edges, [2]K{c.ids[v], c.ids[after]})
+		}
+	}
+	return edges
+}
+
+// Roots returns the keys that don't depend on any other key, in topological
+// order.
+func (c *CompactGraph[K]) Roots() []K {
+	keys := []K{}
+	for v, id := range c.ids {
+		if c.inOff[v] == c.inOff[v+1] {
+			keys = append(keys, id)
+		}
+	}
+	return keys
+}
+
+// Leaves returns the keys that no other key depends on, in topological order.
+func (c *CompactGraph[K]) Leaves() []K {
+	keys := []K{}
+	for v, id := range c.ids {
+		if c.offsets[v] == c.offsets[v+1] {
+			keys = append(keys, id)
+		}
+	}
+	return keys
+}
+
+// Ancestors returns the keys that id transitively depends on, in topological
+// order. It returns nil if id is not in the graph.
+func (c *CompactGraph[K]) Ancestors(id K) []K {
+	v, ok := c.vertex[id]
+	if !ok {
+		return nil
+	}
+	return c.walk(v, c.inOff, c.befores)
+}
+
+// Descendants returns the keys transitively depending on id, in topological
+// order. It returns nil if id is not in the graph.
+func (c *CompactGraph[K]) Descendants(id K) []K {
+	v, ok := c.vertex[id]
+	if !ok {
+		return nil
+	}
+	return c.walk(v, c.offsets, c.afters)
+}
+
+// walk returns the keys reachable from v following the given edges,
+// excluding v itself, in topological order.
+func (c *CompactGraph[K]) walk(v int, offsets, edges []int32) []K {
+	seen := make([]bool, len(c.ids))
+	seen[v] = true
+	stack := []int32{int32(v)}
+	for len(stack) > 0 {
+		w := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, u := range edges[offsets[w]:offsets[w+1]] {
+			if !seen[u] {
+				seen[u] = true
+				stack = append(stack, u)
+			}
+		}
+	}
+	seen[v] = false
+
+	keys := []K{}
+	for w, ok := range seen {
+		if ok {
+			keys = append(keys, c.ids[w])
+		}
+	}
+	return keys
+}
+
+// Reachable reports whether to transitively depends on from. A key is
+// reachable from itself. Unlike Graph.Reachable, nothing is memoized, but
+// only the keys between from and to in the topological order are visited.
+func (c *CompactGraph[K]) Reachable(from, to K) bool {
+	v, ok := c.vertex[from]
+	if !ok {
+		return false
+	}
+	w, ok := c.vertex[to]
+	if !ok || w < v {
+		return false
+	}
+
+	seen := make(map[int32]bool)
+	stack := []int32{int32(v)}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if int(u) == w {
+			return true
+		}
+		for _, after := range c.afters[c.offsets[u]:c.offsets[u+1]] {
+			if int(after) <= w && !seen[after] {
+				seen[after] = true
+				stack = append(stack, after)
+			}
+		}
+	}
+	return false
+}
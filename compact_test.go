@@ -0,0 +1,104 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+	"github.com/onur1/toposort/toposortgen"
+)
+
+func TestGraphCompact(t *testing.T) {
+	g, err := toposort.NewGraphFromEdges(toposortgen.DAG(200, 0.05, 1), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := g.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(c.SortedIDs(), g.SortedIDs()) {
+		t.Fatalf("expected sorted value %v != %v", g.SortedIDs(), c.SortedIDs())
+	}
+	if !sameKeys(c.Edges(), g.Edges()) {
+		t.Fatal("expected the same edges")
+	}
+	if !reflect.DeepEqual(c.Roots(), g.Roots()) || !reflect.DeepEqual(c.Leaves(), g.Leaves()) {
+		t.Fatal("expected the same roots and leaves")
+	}
+	for _, id := range g.SortedIDs()[:20] {
+		if c.Index(id) != g.Index(id) || c.InDegree(id) != g.InDegree(id) || c.OutDegree(id) != g.OutDegree(id) {
+			t.Fatalf("expected the same index and degrees for %v", id)
+		}
+		if !reflect.DeepEqual(c.Descendants(id), g.Descendants(id)) {
+			t.Fatalf("expected descendants %v != %v", g.Descendants(id), c.Descendants(id))
+		}
+		if !reflect.DeepEqual(c.Ancestors(id), g.Ancestors(id)) {
+			t.Fatalf("expected ancestors %v != %v", g.Ancestors(id), c.Ancestors(id))
+		}
+		for _, other := range g.SortedIDs() {
+			if c.Reachable(id, other) != g.Reachable(id, other) {
+				t.Fatalf("expected the same reachability from %v to %v", id, other)
+			}
+		}
+	}
+	if c.Index(-1) != -1 || c.Descendants(-1) != nil || c.Reachable(-1, -1) {
+		t.Fatal("expected unknown keys to be missing")
+	}
+}
+
+func TestNewCompactGraph(t *testing.T) {
+	g, err := toposort.NewGraphFromEdges(toposortgen.DAG(200, 0.05, 1), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+	edges := g.Edges()
+
+	c, err := toposort.NewCompactGraph([]int{-1}, append(edges, edges[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sorted := c.SortedIDs(); sorted[0] != -1 || !g.IsValidOrder(sorted[1:]) {
+		t.Fatalf("unexpected sorted value %v", sorted)
+	}
+	if !sameKeys(c.Edges(), edges) {
+		t.Fatal("expected the same edges, without duplicates")
+	}
+	for _, id := range g.SortedIDs() {
+		if c.InDegree(id) != g.InDegree(id) || c.OutDegree(id) != g.OutDegree(id) {
+			t.Fatalf("expected the same degrees for %v", id)
+		}
+		if !sameKeys(c.Descendants(id), g.Descendants(id)) || !sameKeys(c.Ancestors(id), g.Ancestors(id)) {
+			t.Fatalf("expected the same descendants and ancestors for %v", id)
+		}
+	}
+
+	testCases := []struct {
+		desc  string
+		edges [][2]int
+		err   error
+	}{
+		{
+			desc:  "cycle",
+			edges: [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}},
+			err:   toposort.ErrCircular,
+		},
+		{
+			desc:  "self reference",
+			edges: [][2]int{{1, 1}},
+			err:   toposort.ErrSelfReference,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := toposort.NewCompactGraph(nil, tt.edges); !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v != %v", tt.err, err)
+			}
+		})
+	}
+}
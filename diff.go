@@ -0,0 +1,60 @@
+package toposort
+
+// GraphDiff lists the changes turning a graph into another.
+type GraphDiff[K comparable] struct {
+	AddedVertices   []K    // keys only in the new graph
+	RemovedVertices []K    // keys only in the old graph
+	AddedEdges      [][2]K // edges only in the new graph
+	RemovedEdges    [][2]K // edges only in the old graph
+}
+
+// Empty reports whether both graphs have the same keys and edges.
+func (d *GraphDiff[K]) Empty() bool {
+	return len(d.AddedVertices) == 0 && len(d.RemovedVertices) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Diff returns the keys and edges added and removed going from a to b. Added
+// keys and edges are listed in the topological order of b, and removed ones
+// in the topological order of a.
+func Diff[K comparable](a, b *Graph[K]) *GraphDiff[K] {
+	d := &GraphDiff[K]{
+		AddedVertices:   []K{},
+		RemovedVertices: []K{},
+		AddedEdges:      [][2]K{},
+		RemovedEdges:    [][2]K{},
+	}
+
+	for _, id := range b.SortedIDs() {
+		if a.Index(id) < 0 {
+			d.AddedVertices = append(d.AddedVertices, id)
+		}
+	}
+	for _, id := range a.SortedIDs() {
+		if b.Index(id) < 0 {
+			d.RemovedVertices = append(d.RemovedVertices, id)
+		}
+	}
+
+	aEdges, bEdges := a.Edges(), b.Edges()
+	inA := make(map[[2]K]bool, len(aEdges))
+	for _, e := range aEdges {
+		inA[e] = true
+	}
+	inB := make(map[[2]K]bool, len(bEdges))
+	for _, e := range bEdges {
+		inB[e] = true
+	}
+	for _, e := range bEdges {
+		if !inA[e] {
+			d.AddedEdges = append(d.AddedEdges, e)
+		}
+	}
+	for _, e := range aEdges {
+		if !inB[e] {
+			d.RemovedEdges = append(d.RemovedEdges, e)
+		}
+	}
+
+	return d
+}
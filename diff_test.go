@@ -0,0 +1,38 @@
+package toposort_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestDiff(t *testing.T) {
+	a := newExampleGraph(t)
+	b, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Jonas",
+		"Ruby":    "Barbara",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := toposort.Diff(a, b)
+
+	expected := &toposort.GraphDiff[string]{
+		AddedVertices:   []string{"Ruby"},
+		RemovedVertices: []string{"Sophie"},
+		AddedEdges:      [][2]string{{"Jonas", "Nick"}, {"Barbara", "Ruby"}},
+		RemovedEdges:    [][2]string{{"Jonas", "Sophie"}, {"Sophie", "Nick"}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Fatalf("expected diff %+v != %+v", expected, d)
+	}
+	if d.Empty() {
+		t.Fatal("expected a non-empty diff")
+	}
+	if d := toposort.Diff(a, a); !d.Empty() {
+		t.Fatalf("expected an empty diff, got %+v", d)
+	}
+}
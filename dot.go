@@ -0,0 +1,280 @@
+package toposort
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidDOT is raised when a DOT graph can't be parsed.
+var ErrInvalidDOT = errors.New("invalid dot")
+
+// DOT writes the graph to w in Graphviz DOT format.
+//
+// Vertices are written in topological order and edges point from each key to
+// the keys depending on it, followed by the edges left out to break cycles,
+// as listed by DroppedEdges, which are dashed and colored red. Edges with a
+// label are labeled.
+func (g *Graph[K]) DOT(w io.Writer) error {
+	var b bytes.Buffer
+
+	b.WriteString("digraph {\n")
+	for _, v := range g.sorted {
+		fmt.Fprintf(&b, "\t%s;\n", dotID(g.ids[v]))
+	}
+	for _, v := range g.sorted {
+		for _, after := range g.afters[v] {
+			fmt.Fprintf(&b, "\t%s -> %s", dotID(g.ids[v]), dotID(g.ids[after]))
+			if label := g.labels[[2]K{g.ids[v], g.ids[after]}]; label != "" {
+				fmt.Fprintf(&b, " [label=%s]", strconv.Quote(label))
+			}
+			b.WriteString(";\n")
+		}
+	}
+	for _, e := range g.brokenEdges() {
+		fmt.Fprintf(&b, "\t%s -> %s [color=red, style=dashed", dotID(e.From), dotID(e.To))
+		if e.Label != "" {
+			fmt.Fprintf(&b, ", label=%s", strconv.Quote(e.Label))
+		}
+		b.WriteString("];\n")
+	}
+	b.WriteString("}\n")
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// brokenEdges returns the edges left out of the graph to break cycles, as
+// listed by DroppedEdges, between keys still in the graph.
+func (g *Graph[K]) brokenEdges() []Edge[K] {
+	edges := []Edge[K]{}
+	for _, e := range g.dropped {
+		_, ok := g.vertex[e.From]
+		if _, ok2 := g.vertex[e.To]; ok && ok2 && !g.hasEdge(e.From, e.To) {
+			edges = append(edges, e)
+		}
+	}
+
+	return edges
+}
+
+// dotID formats a key as a quoted DOT identifier.
+func dotID[K comparable](id K) string {
+	return strconv.Quote(fmt.Sprint(id))
+}
+
+// ParseDOT builds a graph from a Graphviz DOT digraph read from r.
+//
+// Only a simple subset of the language is understood: node statements like
+// a; and edge statements like a -> b -> c; optionally followed by attribute
+// lists, which are ignored along with graph attribute statements. Subgraphs
+// and undirected edges are rejected with ErrInvalidDOT.
+func ParseDOT(r io.Reader, opts ...Option) (*Graph[string], error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &dotScanner{src: string(src), line: 1}
+
+	tok := s.next()
+	if tok == "strict" {
+		tok = s.next()
+	}
+	if tok != "digraph" {
+		return nil, s.errorf("expected digraph, found %q", tok)
+	}
+	if tok = s.next(); tok != "{" {
+		tok = s.next() // graph name
+	}
+	if tok != "{" {
+		return nil, s.errorf("expected {, found %q", tok)
+	}
+
+	var (
+		ids   []string
+		edges [][2]string
+	)
+
+	for {
+		tok = s.next()
+		switch tok {
+		case "}":
+			if tok = s.next(); tok != "" {
+				return nil, s.errorf("unexpected %q after graph", tok)
+			}
+			return newGraph(context.Background(), ids, edges, newOptions(opts))
+		case ";":
+			continue
+		case "graph", "node", "edge", "subgraph":
+			if s.peek() != "[" {
+				return nil, s.errorf("unsupported %s statement", tok)
+			}
+			if err := s.skipAttrs(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !s.isID(tok) {
+			return nil, s.errorf("unexpected %q", tok)
+		}
+
+		id := s.unquote(tok)
+
+		if s.peek() == "=" { // graph attribute
+			s.next()
+			if tok = s.next(); !s.isID(tok) {
+				return nil, s.errorf("expected attribute value, found %q", tok)
+			}
+			continue
+		}
+
+		ids = append(ids, id)
+
+		for s.peek() == "->" || s.peek() == "--" {
+			if s.next() == "--" {
+				return nil, s.errorf("undirected edges are not supported")
+			}
+			if tok = s.next(); !s.isID(tok) {
+				return nil, s.errorf("expected node, found %q", tok)
+			}
+			afterID := s.unquote(tok)
+			edges = append(edges, [2]string{id, afterID})
+			id = afterID
+		}
+
+		if s.peek() == "[" {
+			if err := s.skipAttrs(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// dotScanner splits DOT source into tokens.
+type dotScanner struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (s *dotScanner) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidDOT, s.line, fmt.Sprintf(format, args...))
+}
+
+// peek returns the next token without consuming it.
+func (s *dotScanner) peek() string {
+	pos, line := s.pos, s.line
+	tok := s.next()
+	s.pos, s.line = pos, line
+	return tok
+}
+
+// next consumes and returns the next token, or "" at the end of input.
+func (s *dotScanner) next() string {
+	s.skipSpace()
+	if s.pos >= len(s.src) {
+		return ""
+	}
+
+	start := s.pos
+	switch c := s.src[s.pos]; {
+	case c == '"':
+		s.pos++
+		for s.pos < len(s.src) && s.src[s.pos] != '"' {
+			if s.src[s.pos] == '\\' {
+				s.pos++
+			}
+			if s.pos < len(s.src) && s.src[s.pos] == '\n' {
+				s.line++
+			}
+			s.pos++
+		}
+		s.pos++
+		if s.pos > len(s.src) {
+			s.pos = len(s.src)
+		}
+	case strings.HasPrefix(s.src[s.pos:], "->"), strings.HasPrefix(s.src[s.pos:], "--"):
+		s.pos += 2
+	case strings.IndexByte("{}[];=,", c) >= 0:
+		s.pos++
+	default:
+		for s.pos < len(s.src) && isDOTIDByte(s.src[s.pos]) {
+			s.pos++
+		}
+		if s.pos == start {
+			s.pos++
+		}
+	}
+
+	return s.src[start:s.pos]
+}
+
+// skipSpace skips white space and comments.
+func (s *dotScanner) skipSpace() {
+	for s.pos < len(s.src) {
+		switch c := s.src[s.pos]; {
+		case c == '\n':
+			s.line++
+			s.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			s.pos++
+		case c == '#' || strings.HasPrefix(s.src[s.pos:], "//"):
+			for s.pos < len(s.src) && s.src[s.pos] != '\n' {
+				s.pos++
+			}
+		case strings.HasPrefix(s.src[s.pos:], "/*"):
+			end := strings.Index(s.src[s.pos+2:], "*/")
+			if end < 0 {
+				end = len(s.src) - s.pos - 2
+			}
+			s.line += strings.Count(s.src[s.pos:s.pos+2+end], "\n")
+			s.pos += end + 4
+			if s.pos > len(s.src) {
+				s.pos = len(s.src)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// skipAttrs consumes an attribute list enclosed in brackets.
+func (s *dotScanner) skipAttrs() error {
+	s.next() // [
+	for {
+		switch tok := s.next(); tok {
+		case "]":
+			return nil
+		case "":
+			return s.errorf("unterminated attribute list")
+		}
+	}
+}
+
+// isID reports whether tok is a DOT identifier.
+func (s *dotScanner) isID(tok string) bool {
+	return tok != "" && (tok[0] == '"' || isDOTIDByte(tok[0]))
+}
+
+// unquote returns the value of an identifier token.
+func (s *dotScanner) unquote(tok string) string {
+	if len(tok) < 2 || tok[0] != '"' {
+		return tok
+	}
+	if v, err := strconv.Unquote(tok); err == nil {
+		return v
+	}
+	return strings.ReplaceAll(tok[1:len(tok)-1], `\"`, `"`)
+}
+
+func isDOTIDByte(c byte) bool {
+	return c == '_' || c == '.' || c >= 0x80 ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
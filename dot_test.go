@@ -0,0 +1,165 @@
+package toposort_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphDOT(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := g.DOT(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `digraph {
+	"Jonas";
+	"Sophie";
+	"Nick";
+	"Barbara";
+	"Jonas" -> "Sophie";
+	"Sophie" -> "Nick";
+	"Nick" -> "Barbara";
+}
+`
+	if b.String() != expected {
+		t.Fatalf("expected DOT output %q != %q", expected, b.String())
+	}
+}
+
+func TestGraphDOTDroppedEdges(t *testing.T) {
+	g, err := toposort.NewGraphFromEdges([]toposort.Edge[string]{
+		{From: "a", To: "b"},
+		{From: "b", To: "c"},
+		{From: "c", To: "a", Label: "back"},
+	}, toposort.WithOptionalLabels("back"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := g.DOT(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `digraph {
+	"a";
+	"b";
+	"c";
+	"a" -> "b";
+	"b" -> "c";
+	"c" -> "a" [color=red, style=dashed, label="back"];
+}
+`
+	if b.String() != expected {
+		t.Fatalf("expected DOT output %q != %q", expected, b.String())
+	}
+}
+
+func TestParseDOT(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		src    string
+		sorted []string
+		err    error
+	}{
+		{
+			desc: "edges",
+			src: `digraph {
+	Jonas -> Sophie;
+	Sophie -> Nick -> Barbara;
+}`,
+			sorted: []string{"Jonas", "Sophie", "Nick", "Barbara"},
+		},
+		{
+			desc: "attributes and comments",
+			src: `strict digraph deps {
+	// the supervisor chain
+	rankdir=LR;
+	node [shape=box];
+	"Jonas" -> "Sophie" [color="red"]
+	/* a multi-line
+	   comment */
+	"Sophie" -> "Nick"; # trailing comment
+}`,
+			sorted: []string{"Jonas", "Sophie", "Nick"},
+		},
+		{
+			desc: "cyclic",
+			src:  `digraph { a -> b -> a }`,
+			err:  toposort.ErrCircular,
+		},
+		{
+			desc: "undirected",
+			src:  `digraph { a -- b }`,
+			err:  toposort.ErrInvalidDOT,
+		},
+		{
+			desc: "not a digraph",
+			src:  `graph { a -> b }`,
+			err:  toposort.ErrInvalidDOT,
+		},
+		{
+			desc: "unterminated",
+			src:  `digraph { a -> b`,
+			err:  toposort.ErrInvalidDOT,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			g, err := toposort.ParseDOT(strings.NewReader(tt.src))
+			if tt.err == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sorted := g.SortedIDs(); !reflect.DeepEqual(sorted, tt.sorted) {
+					t.Fatalf("expected sorted value %+v != %+v", tt.sorted, sorted)
+				}
+				return
+			}
+			if errors.Is(err, tt.err) {
+				return
+			}
+			t.Fatalf("expected error %v != %v", tt.err, err)
+		})
+	}
+}
+
+func TestParseDOTRoundTrip(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := g.DOT(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := toposort.ParseDOT(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.SortedIDs(), g.SortedIDs()) {
+		t.Fatalf("expected sorted value %+v != %+v", g.SortedIDs(), parsed.SortedIDs())
+	}
+}
@@ -0,0 +1,205 @@
+package toposort
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidEdgeList is raised when an edge list can't be parsed.
+var ErrInvalidEdgeList = errors.New("invalid edge list")
+
+// Format is the format of a graph, read by ReadEdges or written by Export.
+// ReadEdges only reads the edge list formats.
+type Format int
+
+const (
+	// FormatPairs is a list of white space separated pairs, like "a b", one
+	// per line, where b depends on a.
+	FormatPairs Format = iota
+	// FormatArrows is a list of arrows, like "a -> b", one per line, where b
+	// depends on a. Arrows can be chained, as in "a -> b -> c".
+	FormatArrows
+	// FormatDOT is the Graphviz DOT format, as written by DOT.
+	FormatDOT
+	// FormatMermaid is a Mermaid flowchart, as returned by Mermaid.
+	FormatMermaid
+	// FormatGraphML is the GraphML format, as written by WriteGraphML.
+	FormatGraphML
+	// FormatPlantUML is a PlantUML diagram.
+	FormatPlantUML
+	// FormatD2 is a D2 diagram.
+	FormatD2
+	// FormatTsort is the input format of POSIX tsort(1), white space
+	// separated pairs of keys, like FormatPairs, except that pairs may span
+	// lines, and a pair of the same key, like "a a", declares a key without
+	// edges. Lines starting with # are not comments.
+	FormatTsort
+)
+
+// ReadEdges builds a graph from an edge list read line by line from r in the
+// given format. A line holding a single key declares a key without edges.
+// Blank lines and lines starting with # are skipped.
+func ReadEdges(r io.Reader, format Format, opts ...Option) (*Graph[string], error) {
+	if format == FormatTsort {
+		return readTsort(r, opts)
+	}
+
+	var (
+		ids   []string
+		edges [][2]string
+	)
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var fields []string
+		switch format {
+		case FormatPairs:
+			if fields = strings.Fields(text); len(fields) > 2 {
+				return nil, fmt.Errorf("%w: line %d: expected a pair, found %q", ErrInvalidEdgeList, line, text)
+			}
+		case FormatArrows:
+			fields = strings.Split(text, "->")
+			for i, f := range fields {
+				if fields[i] = strings.TrimSpace(f); fields[i] == "" {
+					return nil, fmt.Errorf("%w: line %d: missing key in %q", ErrInvalidEdgeList, line, text)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown format %d", ErrInvalidEdgeList, format)
+		}
+
+		if len(fields) == 1 {
+			ids = append(ids, fields[0])
+		}
+		for i := 1; i < len(fields); i++ {
+			edges = append(edges, [2]string{fields[i-1], fields[i]})
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return newGraph(context.Background(), ids, edges, newOptions(opts))
+}
+
+// readTsort builds a graph from pairs of keys in the format of tsort(1).
+func readTsort(r io.Reader, opts []Option) (*Graph[string], error) {
+	var (
+		ids   []string
+		edges [][2]string
+	)
+
+	s := bufio.NewScanner(r)
+	s.Split(bufio.ScanWords)
+	for s.Scan() {
+		p := s.Text()
+		if !s.Scan() {
+			if err := s.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: odd number of keys, %q is unpaired", ErrInvalidEdgeList, p)
+		}
+		if c := s.Text(); c == p {
+			ids = append(ids, p)
+		} else {
+			edges = append(edges, [2]string{p, c})
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return newGraph(context.Background(), ids, edges, newOptions(opts))
+}
+
+// NewGraphFromCSV builds a graph from CSV records read from r, where the key
+// in column childCol depends on the key in column parentCol, both counted
+// from 0. A record with an empty parent declares a key without a parent,
+// blank with WithTrimSpace. With WithCSVHeader, the first record is skipped.
+func NewGraphFromCSV(r io.Reader, childCol, parentCol int, opts ...Option) (*Graph[string], error) {
+	if childCol < 0 || parentCol < 0 {
+		return nil, fmt.Errorf("%w: negative column in %d and %d", ErrInvalidEdgeList, childCol, parentCol)
+	}
+
+	o := newOptions(opts)
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	var (
+		ids   []string
+		edges [][2]string
+	)
+
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && o.csvHeader {
+			continue
+		}
+		if childCol >= len(record) || parentCol >= len(record) {
+			return nil, fmt.Errorf("%w: record %d: expected columns %d and %d, found %d columns", ErrInvalidEdgeList, line, childCol, parentCol, len(record))
+		}
+		c, p := record[childCol], record[parentCol]
+		if o.trimSpace {
+			c, p = strings.TrimSpace(c), strings.TrimSpace(p)
+		}
+		if c == "" {
+			return nil, fmt.Errorf("%w: record %d: empty key in column %d", ErrInvalidEdgeList, line, childCol)
+		}
+		if p == "" {
+			ids = append(ids, c)
+		} else {
+			edges = append(edges, [2]string{p, c})
+		}
+	}
+
+	return newGraph(context.Background(), ids, edges, o)
+}
+
+// ReadDependencies builds a graph from a JSON object read from r, mapping
+// each key to the list of keys it depends on, like
+//
+//	{"build": ["fetch"], "test": ["build"], "fetch": []}
+//
+// Keys are added in lexical order, so that the same input always produces
+// the same graph.
+func ReadDependencies(r io.Reader, opts ...Option) (*Graph[string], error) {
+	var deps map[string][]string
+	if err := json.NewDecoder(r).Decode(&deps); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEdgeList, err)
+	}
+
+	ids := make([]string, 0, len(deps))
+	for id := range deps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	edges := [][2]string{}
+	for _, id := range ids {
+		for _, dep := range deps[id] {
+			edges = append(edges, [2]string{dep, id})
+		}
+	}
+
+	return newGraph(context.Background(), ids, edges, newOptions(opts))
+}
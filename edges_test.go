@@ -0,0 +1,162 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestReadEdges(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		src    string
+		format toposort.Format
+		sorted []string
+		err    error
+	}{
+		{
+			desc:   "pairs",
+			src:    "# supervisors\nJonas Sophie\n\nSophie  Nick\nNick\tBarbara\n",
+			format: toposort.FormatPairs,
+			sorted: []string{"Jonas", "Sophie", "Nick", "Barbara"},
+		},
+		{
+			desc:   "arrows",
+			src:    "Jonas -> Sophie -> Nick\nNick->Barbara",
+			format: toposort.FormatArrows,
+			sorted: []string{"Jonas", "Sophie", "Nick", "Barbara"},
+		},
+		{
+			desc:   "tsort",
+			src:    "Jonas Sophie Sophie\nNick #Barbara #Barbara\nNick #Barbara",
+			format: toposort.FormatTsort,
+			sorted: []string{"Jonas", "Sophie", "Nick", "#Barbara"},
+		},
+		{
+			desc:   "odd tsort keys",
+			src:    "a b\nc",
+			format: toposort.FormatTsort,
+			err:    toposort.ErrInvalidEdgeList,
+		},
+		{
+			desc:   "too many keys",
+			src:    "a b c",
+			format: toposort.FormatPairs,
+			err:    toposort.ErrInvalidEdgeList,
+		},
+		{
+			desc:   "dangling arrow",
+			src:    "a ->",
+			format: toposort.FormatArrows,
+			err:    toposort.ErrInvalidEdgeList,
+		},
+		{
+			desc:   "cyclic",
+			src:    "a b\nb a",
+			format: toposort.FormatPairs,
+			err:    toposort.ErrCircular,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			g, err := toposort.ReadEdges(strings.NewReader(tt.src), tt.format)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sorted := g.SortedIDs(); !reflect.DeepEqual(sorted, tt.sorted) {
+					t.Fatalf("expected sorted value %+v != %+v", tt.sorted, sorted)
+				}
+				return
+			}
+			if errors.Is(err, tt.err) {
+				return
+			}
+			t.Fatalf("expected error %v != %v", tt.err, err)
+		})
+	}
+}
+
+func TestNewGraphFromCSV(t *testing.T) {
+	src := `name,title,manager
+Barbara,Engineer,Nick
+"Nick","Manager, Platform",Sophie
+Sophie,Director,Jonas
+Jonas,CEO,
+`
+
+	g, err := toposort.NewGraphFromCSV(strings.NewReader(src), 0, 2, toposort.WithCSVHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Jonas", "Sophie", "Nick", "Barbara"}
+	if sorted := g.SortedIDs(); !reflect.DeepEqual(sorted, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, sorted)
+	}
+
+	if _, err := toposort.NewGraphFromCSV(strings.NewReader(src), 0, 3); !errors.Is(err, toposort.ErrInvalidEdgeList) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidEdgeList, err)
+	}
+	if _, err := toposort.NewGraphFromCSV(strings.NewReader(src), -1, 2); !errors.Is(err, toposort.ErrInvalidEdgeList) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidEdgeList, err)
+	}
+}
+
+func TestReadDependencies(t *testing.T) {
+	g, err := toposort.ReadDependencies(strings.NewReader(`{
+	"test": ["build"],
+	"build": ["fetch", "configure"],
+	"configure": ["fetch"],
+	"fetch": []
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"fetch", "configure", "build", "test"}
+	if sorted := g.SortedIDs(); !reflect.DeepEqual(sorted, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, sorted)
+	}
+
+	if _, err := toposort.ReadDependencies(strings.NewReader(`{"a": "b"}`)); !errors.Is(err, toposort.ErrInvalidEdgeList) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidEdgeList, err)
+	}
+}
+
+func TestDuplicateEdges(t *testing.T) {
+	src := "a b\nb c\na b\n"
+
+	g, err := toposort.ReadEdges(strings.NewReader(src), toposort.FormatPairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := [][2]string{{"a", "b"}, {"b", "c"}}; !reflect.DeepEqual(g.Edges(), expected) {
+		t.Fatalf("expected edges %v != %v", expected, g.Edges())
+	}
+	if err := g.AddEdge("b", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if n := g.OutDegree("b"); n != 1 {
+		t.Fatalf("expected a single edge out of b, got %d", n)
+	}
+
+	_, err = toposort.ReadEdges(strings.NewReader(src), toposort.FormatPairs, toposort.WithStrictEdges())
+	if !errors.Is(err, toposort.ErrDuplicateEdge) {
+		t.Fatalf("expected error %v != %v", toposort.ErrDuplicateEdge, err)
+	}
+	if expected := "duplicate edge: a -> b"; err.Error() != expected {
+		t.Fatalf("expected error %q != %q", expected, err.Error())
+	}
+
+	g, err = toposort.ReadEdges(strings.NewReader("a b\n"), toposort.FormatPairs, toposort.WithStrictEdges())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdge("a", "b"); !errors.Is(err, toposort.ErrDuplicateEdge) {
+		t.Fatalf("expected error %v != %v", toposort.ErrDuplicateEdge, err)
+	}
+}
@@ -27,5 +27,5 @@ func ExampleSort() {
 
 	// Output:
 	// [Jonas Sophie Nick Barbara]
-	// cyclic: [Jonas Jonas]
+	// cyclic: self reference: Jonas
 }
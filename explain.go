@@ -0,0 +1,69 @@
+package toposort
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation tells why a key is placed where it is in the topological
+// order of a graph.
+type Explanation[K comparable] struct {
+	ID K
+	// Index is the position of the key in the topological order.
+	Index int
+	// Depth is the length of the longest dependency chain leading to the
+	// key, the number of stages of Plan run before it.
+	Depth int
+	// Parents lists the keys the key directly depends on, in order.
+	Parents []K
+	// Chain is the heaviest dependency chain from a root to the key, the
+	// longest one without weights, which holds the key back the most.
+	Chain []K
+	// ChainWeight is the sum of the weights of the keys of Chain.
+	ChainWeight float64
+}
+
+// Explain tells why id is placed where it is, like why a build step runs so
+// late. It returns an error wrapping ErrNotFound if id is not in the graph.
+func (g *Graph[K]) Explain(id K) (*Explanation[K], error) {
+	v, ok := g.lookup(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, id)
+	}
+
+	depths, _ := g.depths()
+	weights, prev := g.heaviestChains()
+
+	return &Explanation[K]{
+		ID:          id,
+		Index:       g.position[v],
+		Depth:       depths[v],
+		Parents:     g.Parents(id),
+		Chain:       g.keys(chainTo(prev, v)),
+		ChainWeight: weights[v],
+	}, nil
+}
+
+// String describes the explanation in plain text, a fact per line.
+func (e *Explanation[K]) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%v is at index %d, depth %d\n", e.ID, e.Index, e.Depth)
+	if len(e.Parents) == 0 {
+		b.WriteString("it depends on nothing\n")
+	} else {
+		fmt.Fprintf(&b, "it depends directly on %s\n", joinKeys(e.Parents, ", "))
+	}
+	fmt.Fprintf(&b, "its longest chain is %s, of weight %v\n", joinKeys(e.Chain, " -> "), e.ChainWeight)
+
+	return b.String()
+}
+
+// joinKeys formats keys joined by sep.
+func joinKeys[K comparable](keys []K, sep string) string {
+	s := make([]string, len(keys))
+	for i, id := range keys {
+		s[i] = fmt.Sprint(id)
+	}
+	return strings.Join(s, sep)
+}
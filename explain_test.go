@@ -0,0 +1,50 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphExplain(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d -> f;
+	a -> c -> f;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetWeight("c", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := g.Explain("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &toposort.Explanation[string]{
+		ID:          "f",
+		Index:       4,
+		Depth:       3,
+		Parents:     []string{"c", "d"},
+		Chain:       []string{"a", "c", "f"},
+		ChainWeight: 7,
+	}
+	if !reflect.DeepEqual(e, expected) {
+		t.Fatalf("expected explanation %+v != %+v", expected, e)
+	}
+
+	text := "f is at index 4, depth 3\n" +
+		"it depends directly on c, d\n" +
+		"its longest chain is a -> c -> f, of weight 7\n"
+	if e.String() != text {
+		t.Fatalf("expected text %q != %q", text, e.String())
+	}
+
+	if _, err := g.Explain("g"); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+}
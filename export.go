@@ -0,0 +1,133 @@
+package toposort
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Export writes the graph to w in the given format, so that it can be fed to
+// the renderers of a docs pipeline, or read back with ReadEdges in the edge
+// list formats.
+//
+// Vertices are written in topological order and edges point from each key to
+// the keys depending on it. Keys written as pairs must not contain spaces.
+func (g *Graph[K]) Export(w io.Writer, format Format) error {
+	switch format {
+	case FormatPairs, FormatArrows, FormatTsort:
+		return g.writeEdgeList(w, format)
+	case FormatDOT:
+		return g.DOT(w)
+	case FormatMermaid:
+		_, err := io.WriteString(w, g.Mermaid())
+		return err
+	case FormatGraphML:
+		return g.WriteGraphML(w)
+	case FormatPlantUML:
+		return g.writePlantUML(w)
+	case FormatD2:
+		return g.writeD2(w)
+	}
+	return fmt.Errorf("unknown format %d", format)
+}
+
+// writeEdgeList writes the graph as an edge list, one edge per line, and
+// the keys without edges on their own, or paired with themselves for tsort.
+func (g *Graph[K]) writeEdgeList(w io.Writer, format Format) error {
+	sep := " "
+	if format == FormatArrows {
+		sep = " -> "
+	}
+
+	var b bytes.Buffer
+
+	for _, v := range g.sorted {
+		if len(g.afters[v]) == 0 && len(g.befores[v]) == 0 {
+			if format == FormatTsort {
+				fmt.Fprintf(&b, "%v %[1]v\n", g.ids[v])
+			} else {
+				fmt.Fprintf(&b, "%v\n", g.ids[v])
+			}
+		}
+		for _, after := range g.afters[v] {
+			fmt.Fprintf(&b, "%v%s%v\n", g.ids[v], sep, g.ids[after])
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// writePlantUML writes the graph as a PlantUML diagram, followed by the edges
+// dropped to break cycles, which are dashed and colored red. Edges with a
+// label are labeled.
+func (g *Graph[K]) writePlantUML(w io.Writer) error {
+	var b bytes.Buffer
+
+	b.WriteString("@startuml\n")
+	for i, v := range g.sorted {
+		fmt.Fprintf(&b, "rectangle %s as n%d\n", strconv.Quote(fmt.Sprint(g.ids[v])), i)
+	}
+	for _, v := range g.sorted {
+		for _, after := range g.afters[v] {
+			fmt.Fprintf(&b, "n%d --> n%d", g.position[v], g.position[after])
+			writePlantUMLLabel(&b, g.labels[[2]K{g.ids[v], g.ids[after]}])
+		}
+	}
+	for _, e := range g.brokenEdges() {
+		fmt.Fprintf(&b, "n%d -[#red,dashed]-> n%d", g.position[g.vertex[e.From]], g.position[g.vertex[e.To]])
+		writePlantUMLLabel(&b, e.Label)
+	}
+	b.WriteString("@enduml\n")
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// writePlantUMLLabel ends a PlantUML edge, labeled if label isn't empty.
+func writePlantUMLLabel(b *bytes.Buffer, label string) {
+	if label != "" {
+		fmt.Fprintf(b, " : %s", strings.ReplaceAll(label, "\n", `\n`))
+	}
+	b.WriteString("\n")
+}
+
+// writeD2 writes the graph as a D2 diagram, followed by the edges dropped to
+// break cycles, which are dashed and colored red. Edges with a label are
+// labeled.
+func (g *Graph[K]) writeD2(w io.Writer) error {
+	var b bytes.Buffer
+
+	for _, v := range g.sorted {
+		fmt.Fprintf(&b, "%s\n", d2ID(g.ids[v]))
+	}
+	for _, v := range g.sorted {
+		for _, after := range g.afters[v] {
+			fmt.Fprintf(&b, "%s -> %s", d2ID(g.ids[v]), d2ID(g.ids[after]))
+			if label := g.labels[[2]K{g.ids[v], g.ids[after]}]; label != "" {
+				fmt.Fprintf(&b, ": %s", strconv.Quote(label))
+			}
+			b.WriteString("\n")
+		}
+	}
+	for _, e := range g.brokenEdges() {
+		fmt.Fprintf(&b, "%s -> %s", d2ID(e.From), d2ID(e.To))
+		if e.Label != "" {
+			fmt.Fprintf(&b, ": %s", strconv.Quote(e.Label))
+		}
+		b.WriteString(" {style.stroke: red; style.stroke-dash: 3}\n")
+	}
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// d2ID formats a key as a quoted D2 identifier.
+func d2ID[K comparable](id K) string {
+	return strconv.Quote(fmt.Sprint(id))
+}
@@ -0,0 +1,142 @@
+package toposort_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphExport(t *testing.T) {
+	g, err := toposort.NewGraphFromEdges([]toposort.Edge[string]{
+		{From: "Jonas", To: "Sophie"},
+		{From: "Sophie", To: "Nick", Label: "mentor"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc     string
+		format   toposort.Format
+		expected string
+	}{
+		{
+			desc:     "pairs",
+			format:   toposort.FormatPairs,
+			expected: "Jonas Sophie\nSophie Nick\n",
+		},
+		{
+			desc:     "arrows",
+			format:   toposort.FormatArrows,
+			expected: "Jonas -> Sophie\nSophie -> Nick\n",
+		},
+		{
+			desc:   "plantuml",
+			format: toposort.FormatPlantUML,
+			expected: `@startuml
+rectangle "Jonas" as n0
+rectangle "Sophie" as n1
+rectangle "Nick" as n2
+n0 --> n1
+n1 --> n2 : mentor
+@enduml
+`,
+		},
+		{
+			desc:   "d2",
+			format: toposort.FormatD2,
+			expected: `"Jonas"
+"Sophie"
+"Nick"
+"Jonas" -> "Sophie"
+"Sophie" -> "Nick": "mentor"
+`,
+		},
+		{
+			desc:     "mermaid",
+			format:   toposort.FormatMermaid,
+			expected: g.Mermaid(),
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := g.Export(&b, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.expected {
+				t.Fatalf("expected output %q != %q", tt.expected, b.String())
+			}
+		})
+	}
+
+	var b bytes.Buffer
+	if err := g.Export(&b, toposort.FormatArrows); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := toposort.ReadEdges(strings.NewReader(b.String()), toposort.FormatArrows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.SortedIDs(), g.SortedIDs()) {
+		t.Fatalf("expected sorted value %v != %v", g.SortedIDs(), loaded.SortedIDs())
+	}
+	if err := g.Export(&b, toposort.Format(-1)); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestGraphExportDroppedEdges(t *testing.T) {
+	g, err := toposort.NewGraphFromEdges([]toposort.Edge[string]{
+		{From: "a", To: "b"},
+		{From: "b", To: "a", Label: "back"},
+	}, toposort.WithOptionalLabels("back"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc     string
+		format   toposort.Format
+		expected string
+	}{
+		{
+			desc:   "plantuml",
+			format: toposort.FormatPlantUML,
+			expected: `@startuml
+rectangle "a" as n0
+rectangle "b" as n1
+n0 --> n1
+n1 -[#red,dashed]-> n0 : back
+@enduml
+`,
+		},
+		{
+			desc:   "d2",
+			format: toposort.FormatD2,
+			expected: `"a"
+"b"
+"a" -> "b"
+"b" -> "a": "back" {style.stroke: red; style.stroke-dash: 3}
+`,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := g.Export(&b, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.expected {
+				t.Fatalf("expected output %q != %q", tt.expected, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package toposort
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// SuggestEdgeRemovals returns edges of relations whose removal makes it
+// acyclic, as [parent, child] pairs, so that a tangled configuration rejected
+// with ErrCircular can be fixed by cutting as few dependencies as possible.
+// It returns no edges if relations is already acyclic.
+//
+// Finding the smallest such set is NP-hard in general, so the edges are
+// picked with the greedy heuristic of Eades, Lin and Smyth, which orders the
+// keys so that few edges point backwards.
+func SuggestEdgeRemovals[K comparable](relations map[K]K) [][2]K {
+	g, _ := unsorted(relations, options{}) // can't fail without options
+	return g.keyEdges(feedbackEdges(g.afters, g.befores))
+}
+
+// unsorted interns relations, normalized with o, into a graph that is
+// neither sorted nor validated, numbering the keys in a deterministic order.
+func unsorted[K comparable](relations map[K]K, o options) (*Graph[K], error) {
+	children := make([]K, 0, len(relations))
+	for c := range relations {
+		children = append(children, c)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return lexicalLess(children[i], children[j])
+	})
+
+	ids, edges := []K{}, make([][2]K, 0, len(relations))
+	for _, c := range children {
+		if s, ok := any(relations[c]).(string); ok && s == "" { // no parent
+			ids = append(ids, c)
+			continue
+		}
+		edges = append(edges, [2]K{relations[c], c})
+	}
+
+	return build(ids, edges, o)
+}
+
+// keyEdges translates edges between vertices to edges between keys.
+func (g *Graph[K]) keyEdges(edges [][2]int) [][2]K {
+	keys := make([][2]K, len(edges))
+	for i, e := range edges {
+		keys[i] = [2]K{g.ids[e[0]], g.ids[e[1]]}
+	}
+	return keys
+}
+
+// feedbackEdges returns a set of edges whose removal makes the given graph
+// acyclic, where afters lists the vertices depending on each vertex and
+// befores the vertices each vertex depends on.
+//
+// The vertices are ordered with the Eades-Lin-Smyth heuristic: sinks are
+// moved to the end and sources to the start as they appear, and otherwise the
+// vertex with the most outgoing edges relative to its incoming edges is moved
+// to the start. The edges pointing backwards in that order are returned,
+// self references included.
+func feedbackEdges(afters, befores [][]int) [][2]int {
+	n := len(afters)
+	indegree := make([]int, n)
+	outdegree := make([]int, n)
+	for v := 0; v < n; v++ {
+		for _, after := range afters[v] {
+			if after != v {
+				outdegree[v]++
+				indegree[after]++
+			}
+		}
+	}
+
+	removed := make([]bool, n)
+	start, end := []int{}, []int{}
+
+	// sources and sinks waiting to be moved, degrees only ever decreasing
+	queue := []int{}
+	for v := 0; v < n; v++ {
+		if indegree[v] == 0 || outdegree[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+
+	remove := func(v int) {
+		removed[v] = true
+		for _, after := range afters[v] {
+			if after != v && !removed[after] {
+				if indegree[after]--; indegree[after] == 0 {
+					queue = append(queue, after)
+				}
+			}
+		}
+		for _, before := range befores[v] {
+			if before != v && !removed[before] {
+				if outdegree[before]--; outdegree[before] == 0 {
+					queue = append(queue, before)
+				}
+			}
+		}
+	}
+
+	for left := n; left > 0; left-- {
+		v := -1
+		for v < 0 && len(queue) > 0 {
+			v = queue[0]
+			queue = queue[1:]
+			if removed[v] {
+				v = -1
+			}
+		}
+		switch {
+		case v < 0:
+			for w := 0; w < n; w++ {
+				if !removed[w] && (v < 0 || outdegree[w]-indegree[w] > outdegree[v]-indegree[v]) {
+					v = w
+				}
+			}
+			start = append(start, v)
+		case outdegree[v] == 0:
+			end = append(end, v)
+		default:
+			start = append(start, v)
+		}
+		remove(v)
+	}
+
+	position := make([]int, n)
+	for i, v := range start {
+		position[v] = i
+	}
+	for i, v := range end {
+		position[v] = n - 1 - i
+	}
+
+	edges := [][2]int{}
+	for _, v := range start {
+		for _, after := range afters[v] {
+			if position[after] <= position[v] {
+				edges = append(edges, [2]int{v, after})
+			}
+		}
+	}
+	for i := len(end) - 1; i >= 0; i-- {
+		v := end[i]
+		for _, after := range afters[v] {
+			if position[after] <= position[v] {
+				edges = append(edges, [2]int{v, after})
+			}
+		}
+	}
+
+	return edges
+}
+
+// CyclePolicy tells how NewGraph handles cyclic input.
+type CyclePolicy int
+
+const (
+	// CycleFail rejects cyclic input with errors wrapping ErrCircular. It's
+	// the default.
+	CycleFail CyclePolicy = iota
+	// CycleIgnore drops the back edges found while sorting the graph
+	// depth-first, that is, the edges closing a cycle as the keys are
+	// visited. It's the cheapest policy, though it may drop more edges
+	// than needed.
+	CycleIgnore
+	// CycleBreakArbitrary drops the few edges picked by the heuristic of
+	// SuggestEdgeRemovals, without regard to their meaning.
+	CycleBreakArbitrary
+	// CycleCallback drops the edges picked by the callback given to
+	// WithCycleCallback.
+	CycleCallback
+)
+
+// breakCycles drops edges of g according to its cycle policy until it's
+// acyclic, recording the dropped edges. Cycles left in g are reported as
+// usual when it's sorted.
+func (g *Graph[K]) breakCycles(ctx context.Context) error {
+	var callback func([]K) int
+	switch g.opts.cyclePolicy {
+	case CycleFail:
+		return nil
+	case CycleBreakArbitrary:
+		g.dropEdges(feedbackEdges(g.afters, g.befores))
+		return nil
+	case CycleCallback:
+		var ok bool
+		if callback, ok = g.opts.cycleCallback.(func([]K) int); !ok {
+			return fmt.Errorf("cycle callback %T does not accept %T keys", g.opts.cycleCallback, *new(K))
+		}
+	}
+
+	for {
+		_, cycles, err := tsort(ctx, g.afters)
+		if err != nil {
+			return err
+		}
+		if len(cycles) == 0 {
+			return nil
+		}
+		edges := make([][2]int, 0, len(cycles))
+		for _, path := range cycles {
+			i := len(path) - 2 // the back edge
+			if callback != nil {
+				if i = callback(g.keys(path)); i < 0 || i >= len(path)-1 {
+					continue // rejected
+				}
+			}
+			edges = append(edges, [2]int{path[i], path[i+1]})
+		}
+		if len(edges) == 0 {
+			return nil
+		}
+		g.dropEdges(edges)
+	}
+}
+
+// dropEdges removes the given edges from g, recording them as dropped.
+func (g *Graph[K]) dropEdges(edges [][2]int) {
+	for _, e := range edges {
+		if !contains(g.afters[e[0]], e[1]) { // picked twice
+			continue
+		}
+		g.afters[e[0]] = without(g.afters[e[0]], e[1])
+		g.befores[e[1]] = without(g.befores[e[1]], e[0])
+		g.dropped = append(g.dropped, Edge[K]{From: g.ids[e[0]], To: g.ids[e[1]]})
+		g.opts.debug("edge dropped to break a cycle", "from", g.ids[e[0]], "to", g.ids[e[1]])
+	}
+}
@@ -0,0 +1,143 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestSuggestEdgeRemovals(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		relations map[string]string
+		expected  [][2]string
+	}{
+		{
+			desc:      "acyclic",
+			relations: map[string]string{"b": "a", "c": "b"},
+			expected:  [][2]string{},
+		},
+		{
+			desc:      "self reference",
+			relations: map[string]string{"a": "a", "c": "b"},
+			expected:  [][2]string{{"a", "a"}},
+		},
+		{
+			desc: "two cycles",
+			relations: map[string]string{
+				"a": "c", "b": "a", "c": "b",
+				"x": "y", "y": "x",
+				"z": "y",
+			},
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			edges := toposort.SuggestEdgeRemovals(tt.relations)
+			if tt.expected != nil && !reflect.DeepEqual(edges, tt.expected) {
+				t.Fatalf("expected edges %v != %v", tt.expected, edges)
+			}
+
+			relations := make(map[string]string, len(tt.relations))
+			for c, p := range tt.relations {
+				relations[c] = p
+			}
+			for _, e := range edges {
+				if relations[e[1]] != e[0] {
+					t.Fatalf("unexpected edge %v", e)
+				}
+				delete(relations, e[1])
+			}
+			_, err := toposort.NewGraph(relations, toposort.WithAllowMultipleRoots())
+			if errors.Is(err, toposort.ErrCircular) {
+				t.Fatalf("expected no cycles after removing %v, got %v", edges, err)
+			}
+			if len(edges) > 2 {
+				t.Fatalf("expected at most an edge per cycle, got %v", edges)
+			}
+		})
+	}
+}
+
+func TestWithCyclePolicy(t *testing.T) {
+	relations := map[string]string{
+		"a": "c", "b": "a", "c": "b",
+		"x": "x",
+		"y": "b",
+	}
+
+	testCases := []struct {
+		desc     string
+		opt      toposort.Option
+		expected int
+	}{
+		{desc: "ignore", opt: toposort.WithCyclePolicy(toposort.CycleIgnore), expected: 2},
+		{desc: "break arbitrary", opt: toposort.WithCyclePolicy(toposort.CycleBreakArbitrary), expected: 2},
+		{
+			desc: "callback",
+			opt: toposort.WithCycleCallback(func(path []string) int {
+				for i := 0; i < len(path)-1; i++ {
+					if path[i+1] == "a" || path[i+1] == "x" {
+						return i
+					}
+				}
+				return -1
+			}),
+			expected: 2,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			g, err := toposort.NewGraph(relations, tt.opt, toposort.WithAllowMultipleRoots())
+			if err != nil {
+				t.Fatal(err)
+			}
+			dropped := g.DroppedEdges()
+			if len(dropped) != tt.expected {
+				t.Fatalf("expected %d dropped edges, got %v", tt.expected, dropped)
+			}
+			for _, e := range dropped {
+				if relations[e.To] != e.From {
+					t.Fatalf("unexpected edge %v", e)
+				}
+				if _, ok := g.EdgeLabel(e.From, e.To); ok {
+					t.Fatalf("expected edge %v to be dropped", e)
+				}
+			}
+			if len(g.Edges()) != len(relations)-len(dropped) {
+				t.Fatalf("expected the other edges to be kept, got %v", g.Edges())
+			}
+		})
+	}
+
+	r, err := toposort.Analyze(
+		map[string]string{"a": "b", "b": "a", "c": "d", "d": "c"},
+		toposort.WithCycleCallback(func(path []string) int {
+			if path[0] == "a" || path[0] == "b" {
+				return 0
+			}
+			return -1
+		}),
+	)
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+	if len(r.Cycles) != 1 || r.Cycles[0][0] != "c" && r.Cycles[0][0] != "d" {
+		t.Fatalf("expected the rejected cycle only, got %v", r.Cycles)
+	}
+
+	_, err = toposort.NewGraph(relations, toposort.WithCycleCallback(func(path []string) int { return -1 }))
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+	_, err = toposort.NewGraph(relations, toposort.WithCycleCallback(func(path []int) int { return 0 }))
+	if err == nil {
+		t.Fatal("expected an error for a callback of another key type")
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/onur1/toposort
 
-go 1.18
+go 1.20
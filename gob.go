@@ -0,0 +1,109 @@
+package toposort
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// errCorruptGob reports encoded data that doesn't make a sorted graph.
+var errCorruptGob = errors.New("corrupt gob data")
+
+// gobGraph is the gob representation of a graph.
+type gobGraph[K comparable] struct {
+	IDs       []K
+	Afters    [][]int
+	Sorted    []int
+	Attrs     []map[string]any
+	Weights   []float64
+	Labels    map[[2]K]string
+	Dropped   []Edge[K]
+	Originals map[K]K
+}
+
+// GobEncode implements gob.GobEncoder, so that a precomputed graph can be
+// cached and loaded again without sorting it. The graph is encoded with its
+// vertices, edges and topological order, along with its attributes, weights,
+// labels and the original spellings of its keys. Concrete types of attribute
+// values must be registered with gob.Register.
+//
+// Options and warnings aren't encoded, so the decoded graph is left with the
+// default options.
+func (g *Graph[K]) GobEncode() ([]byte, error) {
+	v := gobGraph[K]{
+		IDs:       g.ids,
+		Afters:    g.afters,
+		Sorted:    g.sorted,
+		Attrs:     g.attrs,
+		Weights:   g.weights,
+		Labels:    g.labels,
+		Dropped:   g.dropped,
+		Originals: g.originals,
+	}
+
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder. Like UnmarshalJSON, it trusts the
+// encoded order as long as every edge respects it, but without rebuilding the
+// graph, which is checked in time linear to its size.
+func (g *Graph[K]) GobDecode(data []byte) error {
+	var v gobGraph[K]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+		return err
+	}
+
+	n := len(v.IDs)
+	if len(v.Afters) != n || len(v.Sorted) != n ||
+		(v.Attrs != nil && len(v.Attrs) != n) || (v.Weights != nil && len(v.Weights) != n) {
+		return errCorruptGob
+	}
+
+	ng := &Graph[K]{
+		ids:       v.IDs,
+		vertex:    make(map[K]int, n),
+		afters:    v.Afters,
+		befores:   make([][]int, n),
+		sorted:    v.Sorted,
+		position:  make([]int, n),
+		recursive: make([]bool, n),
+		attrs:     v.Attrs,
+		weights:   v.Weights,
+		labels:    v.Labels,
+		dropped:   v.Dropped,
+		originals: v.Originals,
+	}
+	for i, id := range ng.ids {
+		if _, ok := ng.vertex[id]; ok {
+			return errCorruptGob
+		}
+		ng.vertex[id] = i
+	}
+	for i := range ng.position {
+		ng.position[i] = -1
+	}
+	for i, v := range ng.sorted {
+		if v < 0 || v >= n || ng.position[v] >= 0 {
+			return errCorruptGob
+		}
+		ng.position[v] = i
+	}
+	for v, afters := range ng.afters {
+		for _, after := range afters {
+			if after < 0 || after >= n || ng.position[after] <= ng.position[v] {
+				return errCorruptGob
+			}
+			ng.befores[after] = append(ng.befores[after], v)
+		}
+	}
+	ng.invalidate()
+
+	*g = *ng
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package toposort_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphGob(t *testing.T) {
+	g := newExampleGraph(t)
+	if err := g.SetAttr("Nick", "age", 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetWeight("Nick", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetEdgeLabel("Sophie", "Nick", "hard"); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(g); err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded toposort.Graph[string]
+	if err := gob.NewDecoder(&b).Decode(&loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.SortedIDs(), g.SortedIDs()) {
+		t.Fatalf("expected sorted value %v != %v", g.SortedIDs(), loaded.SortedIDs())
+	}
+	if !reflect.DeepEqual(loaded.Edges(), g.Edges()) {
+		t.Fatalf("expected edges %v != %v", g.Edges(), loaded.Edges())
+	}
+	if age, _ := loaded.Attr("Nick", "age"); age != 7 {
+		t.Fatalf("expected attribute 7, got %v", age)
+	}
+	if w := loaded.Weight("Nick"); w != 3 {
+		t.Fatalf("expected weight 3, got %v", w)
+	}
+	if label, _ := loaded.EdgeLabel("Sophie", "Nick"); label != "hard" {
+		t.Fatalf("expected label hard, got %q", label)
+	}
+	if !loaded.Reachable("Jonas", "Barbara") {
+		t.Fatal("expected Barbara to be reachable from Jonas")
+	}
+	if err := loaded.AddEdge("Barbara", "Ruby"); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, &loaded)
+
+	data, err := g.GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other toposort.Graph[string]
+	if err := other.GobDecode(data[:len(data)/2]); err == nil {
+		t.Fatal("expected truncated data to be rejected")
+	}
+}
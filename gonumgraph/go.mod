@@ -0,0 +1,10 @@
+module github.com/onur1/toposort/gonumgraph
+
+go 1.20
+
+require (
+	github.com/onur1/toposort v0.1.1-0.20261016015931-763f1cd09013
+	gonum.org/v1/gonum v0.14.0
+)
+
+replace github.com/onur1/toposort => ../
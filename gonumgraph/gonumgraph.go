@@ -0,0 +1,150 @@
+// Package gonumgraph adapts the graphs of package toposort to the interfaces
+// of gonum.org/v1/gonum/graph, so that the algorithms of gonum, like
+// shortest paths, dominator trees or layouts, run on them without copying
+// their edges, and builds graphs of package toposort from gonum graphs.
+//
+// It's a module of its own, so that package toposort doesn't depend on
+// gonum.
+package gonumgraph
+
+import (
+	"sort"
+
+	"github.com/onur1/toposort"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var _ graph.Directed = (*Directed[string])(nil)
+
+// Directed is a graph.Directed view of a graph, where edges point from each
+// key to the keys depending on it, and the ID of the node of a key is its
+// position in the topological order. The graph must not be mutated while
+// it's viewed.
+type Directed[K comparable] struct {
+	g    *toposort.Graph[K]
+	keys []K // keys by node ID
+}
+
+// New returns a graph.Directed view of g.
+func New[K comparable](g *toposort.Graph[K]) *Directed[K] {
+	return &Directed[K]{g: g, keys: g.SortedIDs()}
+}
+
+// Node is the node of a key.
+type Node[K comparable] struct {
+	id  int64
+	Key K
+}
+
+// ID implements graph.Node.
+func (n Node[K]) ID() int64 {
+	return n.id
+}
+
+// NodeOf returns the node of id, or nil if id is not in the graph.
+func (d *Directed[K]) NodeOf(id K) graph.Node {
+	i := d.g.Index(id)
+	if i < 0 {
+		return nil
+	}
+	return Node[K]{id: int64(i), Key: d.keys[i]}
+}
+
+// Node implements graph.Graph, returning nil if there's no node with the
+// given ID.
+func (d *Directed[K]) Node(id int64) graph.Node {
+	if id < 0 || id >= int64(len(d.keys)) {
+		return nil
+	}
+	return Node[K]{id: id, Key: d.keys[id]}
+}
+
+// Nodes implements graph.Graph, listing the nodes in topological order.
+func (d *Directed[K]) Nodes() graph.Nodes {
+	return d.nodes(d.keys)
+}
+
+// From implements graph.Graph, listing the nodes of the keys depending on
+// the key of the node with the given ID.
+func (d *Directed[K]) From(id int64) graph.Nodes {
+	n, ok := d.Node(id).(Node[K])
+	if !ok {
+		return graph.Empty
+	}
+	return d.nodes(d.g.Children(n.Key))
+}
+
+// To implements graph.Directed, listing the nodes of the keys the key of the
+// node with the given ID depends on.
+func (d *Directed[K]) To(id int64) graph.Nodes {
+	n, ok := d.Node(id).(Node[K])
+	if !ok {
+		return graph.Empty
+	}
+	return d.nodes(d.g.Parents(n.Key))
+}
+
+// HasEdgeBetween implements graph.Graph.
+func (d *Directed[K]) HasEdgeBetween(xid, yid int64) bool {
+	return d.HasEdgeFromTo(xid, yid) || d.HasEdgeFromTo(yid, xid)
+}
+
+// HasEdgeFromTo implements graph.Directed.
+func (d *Directed[K]) HasEdgeFromTo(uid, vid int64) bool {
+	u, ok := d.Node(uid).(Node[K])
+	if !ok {
+		return false
+	}
+	v, ok := d.Node(vid).(Node[K])
+	if !ok {
+		return false
+	}
+	_, ok = d.g.EdgeLabel(u.Key, v.Key)
+	return ok
+}
+
+// Edge implements graph.Graph, returning nil if there's no edge from the
+// node with ID uid to the node with ID vid.
+func (d *Directed[K]) Edge(uid, vid int64) graph.Edge {
+	if !d.HasEdgeFromTo(uid, vid) {
+		return nil
+	}
+	return simple.Edge{F: d.Node(uid), T: d.Node(vid)}
+}
+
+// nodes returns the nodes of the given keys.
+func (d *Directed[K]) nodes(ids []K) graph.Nodes {
+	nodes := make([]graph.Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = d.NodeOf(id)
+	}
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// FromDirected builds a graph from a gonum directed graph, keyed by the IDs
+// of its nodes, with the given options. Nodes and edges are added by
+// increasing IDs, so that the same gonum graph always builds the same graph.
+func FromDirected(dg graph.Directed, opts ...toposort.Option) (*toposort.Graph[int64], error) {
+	ids := nodeIDs(dg.Nodes())
+
+	pairs := [][2]int64{}
+	for _, u := range ids {
+		for _, v := range nodeIDs(dg.From(u)) {
+			pairs = append(pairs, [2]int64{u, v})
+		}
+	}
+
+	return toposort.NewGraphFromPairs(ids, pairs, opts...)
+}
+
+// nodeIDs returns the IDs of the given nodes in increasing order.
+func nodeIDs(nodes graph.Nodes) []int64 {
+	ids := []int64{}
+	for nodes.Next() {
+		ids = append(ids, nodes.Node().ID())
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,82 @@
+package gonumgraph_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+	"github.com/onur1/toposort/gonumgraph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func TestDirected(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := gonumgraph.New(g)
+
+	sorted, err := topo.Sort(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{}
+	for _, n := range sorted {
+		keys = append(keys, n.(gonumgraph.Node[string]).Key)
+	}
+	if expected := []string{"Jonas", "Sophie", "Nick", "Barbara"}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, keys)
+	}
+
+	jonas, sophie := d.NodeOf("Jonas"), d.NodeOf("Sophie")
+	if !d.HasEdgeFromTo(jonas.ID(), sophie.ID()) || d.HasEdgeFromTo(sophie.ID(), jonas.ID()) {
+		t.Fatal("expected an edge from Jonas to Sophie only")
+	}
+	if !d.HasEdgeBetween(sophie.ID(), jonas.ID()) {
+		t.Fatal("expected an edge between Sophie and Jonas")
+	}
+	if e := d.Edge(sophie.ID(), jonas.ID()); e != nil {
+		t.Fatalf("unexpected edge %v", e)
+	}
+	if n := d.To(sophie.ID()); n.Len() != 1 || !n.Next() || n.Node().ID() != jonas.ID() {
+		t.Fatal("expected Sophie to depend on Jonas")
+	}
+	if d.NodeOf("Ruby") != nil || d.Node(4) != nil {
+		t.Fatal("expected no node for keys not in the graph")
+	}
+}
+
+func TestFromDirected(t *testing.T) {
+	dg := simple.NewDirectedGraph()
+	dg.SetEdge(simple.Edge{F: simple.Node(3), T: simple.Node(1)})
+	dg.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	dg.AddNode(simple.Node(0))
+
+	if _, err := gonumgraph.FromDirected(dg); !errors.Is(err, toposort.ErrMultipleRoots) {
+		t.Fatalf("expected error %v != %v", toposort.ErrMultipleRoots, err)
+	}
+
+	g, err := gonumgraph.FromDirected(dg, toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted := g.SortedIDs()
+	if len(sorted) != 4 || g.Index(0) < 0 {
+		t.Fatalf("expected 4 sorted keys, got %v", sorted)
+	}
+	if g.Index(3) > g.Index(1) || g.Index(1) > g.Index(2) {
+		t.Fatalf("expected 3, 1 and 2 in order, got %v", sorted)
+	}
+
+	dg.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(3)})
+	if _, err := gonumgraph.FromDirected(dg, toposort.WithAllowMultipleRoots()); err == nil {
+		t.Fatal("expected an error for a cyclic graph")
+	}
+}
@@ -1,150 +1,728 @@
 package toposort
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 )
 
 var (
 	// ErrCircular is raised when a cyclic relationship has been found.
 	ErrCircular = errors.New("cyclic")
+	// ErrSelfReference is raised when a key depends on itself. It wraps
+	// ErrCircular.
+	ErrSelfReference = fmt.Errorf("%w: self reference", ErrCircular)
 	// ErrMultipleRoots is raised when a graph contains multiple root nodes.
 	ErrMultipleRoots = errors.New("multiple roots")
+	// ErrInvalidName is raised when a key is rejected by the name validator.
+	ErrInvalidName = errors.New("invalid name")
+	// ErrNotFound is raised when a key is not in the graph.
+	ErrNotFound = errors.New("not found")
+	// ErrNotChain is raised when a graph is not a single chain of keys.
+	ErrNotChain = errors.New("not a chain")
+	// ErrDisconnected is raised when a graph is made of several unconnected
+	// components.
+	ErrDisconnected = errors.New("disconnected")
+	// ErrAmbiguousID is raised when distinct keys are normalized to the same
+	// key.
+	ErrAmbiguousID = errors.New("ambiguous id")
+	// ErrDuplicateEdge is raised when the same edge is given more than once.
+	ErrDuplicateEdge = errors.New("duplicate edge")
 )
 
-type Vertex[K comparable] struct {
-	afters []K
-	id     K
+// CycleError reports a cyclic relationship between several keys of a graph.
+//
+// Path lists the keys along the cycle, starting and ending with the same key,
+// e.g. [a b c a] for a → b → c → a. It matches ErrCircular with errors.Is.
+type CycleError[K comparable] struct {
+	Path []K
 }
 
-// tsort sorts the given graph topologically.
-func tsort[K comparable](g map[K]*Vertex[K]) (sorted []K, recursive map[K]bool, recursion []K) {
-	sorted = []K{}
-	visited := make(map[K]bool)
-	recursive = make(map[K]bool) // keys caught in a recursive chain
-	recursion = []K{}            // recursion paths for printing out in the error messages
+func (e *CycleError[K]) Error() string {
+	return fmt.Sprintf("%v: %v", ErrCircular, e.Path)
+}
 
-	var visit func(id K, ancestors []K)
+func (e *CycleError[K]) Unwrap() error {
+	return ErrCircular
+}
 
-	visit = func(id K, ancestors []K) {
-		vertex := g[id]
-		if _, ok := visited[id]; ok {
-			return
+// SelfReferenceError reports a key depending on itself. It matches both
+// ErrSelfReference and ErrCircular with errors.Is.
+type SelfReferenceError[K comparable] struct {
+	ID K
+}
+
+func (e *SelfReferenceError[K]) Error() string {
+	return fmt.Sprintf("%v: %v", ErrSelfReference, e.ID)
+}
+
+func (e *SelfReferenceError[K]) Unwrap() error {
+	return ErrSelfReference
+}
+
+// MultipleRootsError reports a graph with more than one root node.
+//
+// Roots lists the root keys in sorted order. It matches ErrMultipleRoots with
+// errors.Is.
+type MultipleRootsError[K comparable] struct {
+	Roots []K
+}
+
+func (e *MultipleRootsError[K]) Error() string {
+	return fmt.Sprintf("%v: %v", ErrMultipleRoots, e.Roots)
+}
+
+func (e *MultipleRootsError[K]) Unwrap() error {
+	return ErrMultipleRoots
+}
+
+// DisconnectedError reports a graph made of several weakly connected
+// components.
+//
+// Components lists the keys of each component, as returned by
+// Graph.Components. It matches ErrDisconnected with errors.Is.
+type DisconnectedError[K comparable] struct {
+	Components [][]K
+}
+
+func (e *DisconnectedError[K]) Error() string {
+	return fmt.Sprintf("%v: %v", ErrDisconnected, e.Components)
+}
+
+func (e *DisconnectedError[K]) Unwrap() error {
+	return ErrDisconnected
+}
+
+// cancelCheckInterval is the number of steps taken by long running loops
+// between checks for the cancellation of their context.
+const cancelCheckInterval = 1024
+
+// tsort sorts the given graph topologically, where afters lists the vertices
+// depending on each vertex.
+//
+// The graph is traversed depth-first with an explicit stack rather than
+// recursion, so that very deep dependency chains don't exhaust the goroutine
+// stack.
+func tsort(ctx context.Context, afters [][]int) (sorted []int, cycles [][]int, err error) {
+	sorted = make([]int, 0, len(afters))
+	visited := make([]bool, len(afters))
+	cycles = [][]int{} // cycle paths for reporting in the error messages
+
+	// positions of vertices in ancestors, or -1 for vertices not on the
+	// current path, so that back edges are found in constant time
+	onPath := make([]int, len(afters))
+	for v := range onPath {
+		onPath[v] = -1
+	}
+
+	type frame struct {
+		v    int // vertex being visited
+		next int // index of the next edge to follow
+	}
+
+	for k := range afters {
+		if visited[k] {
+			continue
 		}
-		ancestors = append(ancestors, id)
-		visited[id] = true
-		for _, afterID := range vertex.afters {
-			if sliceContains(ancestors, afterID) {
-				recursive[id] = true
-				for _, id := range ancestors {
-					recursive[id] = true
+		visited[k] = true
+		stack := []frame{{v: k}}
+		ancestors := []int{k}
+		onPath[k] = 0
+		for steps := 1; len(stack) > 0; steps++ {
+			if steps%cancelCheckInterval == 0 {
+				if err = ctx.Err(); err != nil {
+					return
+				}
+			}
+			top := &stack[len(stack)-1]
+			if top.next < len(afters[top.v]) {
+				after := afters[top.v][top.next]
+				top.next++
+				if i := onPath[after]; i >= 0 {
+					path := make([]int, 0, len(ancestors)-i+1)
+					path = append(path, ancestors[i:]...)
+					cycles = append(cycles, append(path, after))
+				} else if !visited[after] {
+					visited[after] = true
+					stack = append(stack, frame{v: after})
+					onPath[after] = len(ancestors)
+					ancestors = append(ancestors, after)
 				}
-				recursion = append(recursion, append([]K{id}, ancestors...)...)
-			} else {
-				visit(afterID, ancestors[:])
+				continue
 			}
+			sorted = append(sorted, top.v) // in post-order, reversed below
+			onPath[top.v] = -1
+			stack = stack[:len(stack)-1]
+			ancestors = ancestors[:len(ancestors)-1]
 		}
-		sorted = append([]K{id}, sorted...)
 	}
 
-	for k := range g {
-		visit(k, []K{})
+	for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
 	}
 
 	return
 }
 
+// Graph is a topologically sorted graph built from a set of relations.
+//
+// Keys are interned into contiguous vertex indices, so that the graph itself
+// is made of slices indexed by vertex rather than maps of keys.
+//
+// The query methods of a graph are safe for concurrent use, as long as it's
+// not mutated at the same time. Use a SyncGraph to mix queries and mutations
+// across goroutines.
 type Graph[K comparable] struct {
-	data      map[K]*Vertex[K] // graph itself
-	sorted    []K              // toposorted keys
-	recursive map[K]bool       // recursive keys
-	recursion []K              // recursion paths
+	ids       []K              // keys by vertex
+	vertex    map[K]int        // vertices by key
+	afters    [][]int          // vertices depending on each vertex
+	befores   [][]int          // vertices each vertex depends on
+	sorted    []int            // toposorted vertices
+	position  []int            // positions of vertices in sorted
+	recursive []bool           // vertices in a strongly connected component of a cycle
+	cycles    [][]int          // cycle paths
+	opts      options          // construction options
+	memo      *memo[K]         // memoized queries, dropped on mutation
+	attrs     []map[string]any // attributes of vertices, allocated on demand
+	weights   []float64        // weights of vertices, allocated on demand
+	labels    map[[2]K]string  // labels of edges, allocated on demand
+	dropped   []Edge[K]        // optional edges left out to avoid cycles
+	warnings  MultiError       // validation errors downgraded to warnings
+	originals map[K]K          // spellings of the keys changed by normalization
 }
 
-func Sort[K comparable](relations map[K]K) ([]K, error) {
-	vertices := make(map[K]*Vertex[K])
+// memo memoizes the results of queries that are costly to compute again.
+type memo[K comparable] struct {
+	mu      sync.Mutex
+	reach   map[int][]bool // vertices reachable from each vertex asked for
+	sorted  []K            // keys in topological order
+	depths  []int          // depths of vertices, as returned by depths
+	layers  int            // number of layers, as returned by depths
+	heights []int          // heights of vertices, as returned by heights
+}
+
+// NewGraph builds a graph from relations, where each key depends on its
+// corresponding value, and sorts it topologically. A string key mapped to an
+// empty string, like "Jonas": "", has no parent.
+//
+// Keys are compared as they are, so string keys that only differ in case,
+// like "Nick" and "nick", are distinct vertices, unless WithCaseFolding is
+// given.
+//
+// The returned error is a MultiError listing every cycle found and, unless
+// WithAllowMultipleRoots is given, the roots of a graph with more than one.
+func NewGraph[K comparable](relations map[K]K, opts ...Option) (*Graph[K], error) {
+	ids, edges := relationEdges(relations)
+
+	return newGraph(context.Background(), ids, edges, newOptions(opts))
+}
+
+// NewGraphContext is like NewGraph, but gives up and returns the context's
+// error as soon as ctx is done, so that building graphs from large untrusted
+// inputs can be canceled or time-boxed.
+func NewGraphContext[K comparable](ctx context.Context, relations map[K]K, opts ...Option) (*Graph[K], error) {
+	ids, edges := relationEdges(relations)
 
+	return newGraph(ctx, ids, edges, newOptions(opts))
+}
+
+// relationEdges returns the edges of relations, each pointing from a value
+// to its key, and the keys mapped to an empty string, which have no parent.
+func relationEdges[K comparable](relations map[K]K) (ids []K, edges [][2]K) {
+	edges = make([][2]K, 0, len(relations))
 	for c, p := range relations {
-		if _, ok := vertices[c]; !ok {
-			vertices[c] = &Vertex[K]{id: c}
-		}
-		if _, ok := vertices[p]; !ok {
-			vertices[p] = &Vertex[K]{id: p}
+		if s, ok := any(p).(string); ok && s == "" {
+			ids = append(ids, c)
+		} else {
+			edges = append(edges, [2]K{p, c})
 		}
-		vertices[p].afters = append(vertices[p].afters, c)
 	}
+	return
+}
 
-	g := new(Graph[K])
-	g.sorted, g.recursive, g.recursion = tsort(vertices)
-	g.data = vertices
+// NewGraphFromPairs builds a graph from the given keys and pairs, where the
+// second key of each pair depends on the first, and sorts it topologically.
+// Keys only found in ids are added without edges.
+func NewGraphFromPairs[K comparable](ids []K, pairs [][2]K, opts ...Option) (*Graph[K], error) {
+	return newGraph(context.Background(), ids, pairs, newOptions(opts))
+}
 
-	if err := validateGraph(g); err != nil {
+// newGraph builds a graph from the given keys and edges, each edge pointing
+// from a key to a key depending on it, and sorts it topologically.
+func newGraph[K comparable](ctx context.Context, ids []K, edges [][2]K, o options) (*Graph[K], error) {
+	g, errs, err := analyze(ctx, ids, edges, o)
+	if err != nil {
 		return nil, err
 	}
+	if errs != nil {
+		return nil, errs
+	}
 
-	return g.sorted, nil
+	return g, nil
 }
 
-// validateGraph checks a graph for recursive paths and multiple root nodes.
-func validateGraph[K comparable](g *Graph[K]) (err MultiError) {
-	var visit func(id K)
+// analyze is like newGraph, but returns the graph along with its validation
+// errors, which are the invalid names if any, or the errors found in the
+// graph otherwise. The graph is nil only if err is not.
+func analyze[K comparable](ctx context.Context, ids []K, edges [][2]K, o options) (g *Graph[K], errs MultiError, err error) {
+	if o.metrics != nil {
+		start := time.Now()
+		defer func() {
+			o.metrics(buildMetrics(g, len(edges), time.Since(start), errs != nil || err != nil))
+		}()
+	}
 
-	length := 0
+	if g, err = build(ids, edges, o); err != nil {
+		return nil, nil, err
+	}
+	if o.logger != nil {
+		n := 0
+		for _, afters := range g.afters {
+			n += len(afters)
+		}
+		o.debug("graph built", "keys", len(g.ids), "edges", n, "duplicates", len(edges)-n)
+	}
 
-	visit = func(id K) {
-		v := g.data[id]
-		for _, afterID := range v.afters {
-			length += 1
-			visit(afterID)
+	if !o.skipValidation {
+		errs, g.warnings = g.opts.split(validateNames(g.ids, g.opts.validators, g.spelling))
+	}
+	if err = g.breakCycles(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if g.sorted, g.cycles, err = tsort(ctx, g.afters); err != nil {
+		return nil, nil, err
+	}
+	if o.logger != nil {
+		for _, path := range g.cycles {
+			o.debug("cycle found", "path", g.keys(path))
+		}
+	}
+	if len(g.cycles) == 0 {
+		less, err := g.less()
+		if err != nil {
+			return nil, nil, err
+		}
+		group, err := g.groups()
+		if err != nil {
+			return nil, nil, err
+		}
+		if group != nil && less == nil {
+			less = rankLess(g.sorted)
+		}
+		if less != nil {
+			g.sorted = kahn(g.afters, g.befores, g.sorted, less, group)
 		}
 	}
+	g.position = make([]int, len(g.ids))
+	for i, v := range g.sorted {
+		g.position[v] = i
+	}
+	g.invalidate()
 
-	roots := []K{}
-	var o int
-	for _, id := range g.sorted {
-		o = length
-		length = 0
-		if !g.recursive[id] { // avoid stack overflow
-			visit(id)
-			if length > o {
-				// if the length of the dependencies is increased,
-				// that means we are traversing a new tree.
-				roots = append(roots, id)
+	if o.skipValidation && len(g.cycles) == 0 {
+		g.recursive = make([]bool, len(g.ids))
+		return g, nil, nil
+	}
+	g.markRecursive()
+
+	invalid, warnings := g.opts.split(validateGraph(ctx, g))
+	if err = ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if errs == nil {
+		errs = invalid
+	}
+	g.warnings = append(g.warnings, warnings...)
+	for _, w := range g.warnings {
+		o.debug("error downgraded to a warning", "error", w)
+	}
+
+	return g, errs, nil
+}
+
+// build interns the given keys and edges, normalized according to o, into a
+// graph that is neither sorted nor validated yet.
+func build[K comparable](ids []K, edges [][2]K, o options) (*Graph[K], error) {
+	var originals map[K]K
+	if o.normalize != nil && !o.canonicalKeys {
+		var err error
+		if ids, edges, originals, err = normalizeKeys(ids, edges, o.normalize, o.strictIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	g := &Graph[K]{
+		ids:       make([]K, 0, len(ids)+len(edges)),
+		vertex:    make(map[K]int, len(ids)+len(edges)),
+		opts:      o,
+		originals: originals,
+	}
+	g.opts.canonicalKeys = false // only for the keys given here
+
+	for _, id := range ids {
+		g.intern(id)
+	}
+
+	if workers := o.workers(len(edges)); workers > 1 {
+		if errs := g.buildParallel(edges, workers); errs != nil {
+			return nil, errs
+		}
+		return g, nil
+	}
+
+	// duplicate edges are dropped, unless they're rejected
+	var errs MultiError
+	seen := make(map[[2]int]bool, len(edges))
+	for _, e := range edges {
+		p, c := g.intern(e[0]), g.intern(e[1])
+		if seen[[2]int{p, c}] {
+			if o.strictEdges {
+				errs = append(errs, fmt.Errorf("%w: %v -> %v", ErrDuplicateEdge, g.spelling(e[0]), g.spelling(e[1])))
 			}
+			continue
 		}
+		seen[[2]int{p, c}] = true
+		g.afters[p] = append(g.afters[p], c)
+		g.befores[c] = append(g.befores[c], p)
+	}
+	if errs != nil {
+		return nil, errs
 	}
 
-	null := *(new(K))
+	return g, nil
+}
 
-	recursions, start, ko := [][]K{}, 0, null
-	for i, k := range g.recursion {
-		if k == ko {
-			recursions = append(recursions, g.recursion[start:i+1])
-			start = i + 1
-			ko = null
-		} else if ko == null {
-			ko = k
+// markRecursive marks the vertices on the cycles of a sorted graph.
+func (g *Graph[K]) markRecursive() {
+	g.recursive = make([]bool, len(g.ids))
+	for _, scc := range tarjan(g.afters, g.sorted) {
+		if len(scc) > 1 {
+			for _, v := range scc {
+				g.recursive[v] = true
+			}
+		}
+	}
+	for _, path := range g.cycles {
+		if len(path) == 2 { // self reference
+			g.recursive[path[0]] = true
 		}
 	}
+}
 
-	// add all cyclic dependency errors to the multierror instance
-	for _, xs := range recursions {
-		err = append(err, fmt.Errorf("%w: %v", ErrCircular, xs))
+// intern returns the vertex of id, adding a vertex without edges for it if
+// there's none yet.
+func (g *Graph[K]) intern(id K) int {
+	v, ok := g.vertex[id]
+	if !ok {
+		v = len(g.ids)
+		g.vertex[id] = v
+		g.ids = append(g.ids, id)
+		g.afters = append(g.afters, nil)
+		g.befores = append(g.befores, nil)
 	}
+	return v
+}
 
-	// add multiple roots error after that if found any
-	if len(roots) > 1 {
-		err = append(err, fmt.Errorf("%w: %v", ErrMultipleRoots, roots))
+// keys translates vertices to their keys.
+func (g *Graph[K]) keys(vs []int) []K {
+	keys := make([]K, len(vs))
+	for i, v := range vs {
+		keys[i] = g.ids[v]
+	}
+	return keys
+}
+
+// SortedIDs returns the keys of the graph in topological order.
+//
+// The keys are translated once and the same slice is returned until the
+// graph is mutated, so that hot paths can call it repeatedly. It must not be
+// modified; copy it first if needed.
+func (g *Graph[K]) SortedIDs() []K {
+	g.memo.mu.Lock()
+	defer g.memo.mu.Unlock()
+
+	if g.memo.sorted == nil {
+		g.memo.sorted = g.keys(g.sorted)
+	}
+	return g.memo.sorted[:len(g.sorted):len(g.sorted)]
+}
+
+// SortedIDsReverse returns the keys of the graph in reverse topological
+// order, where every key comes before the keys it depends on, like the order
+// to stop services started in topological order. It returns a new slice on
+// every call.
+func (g *Graph[K]) SortedIDsReverse() []K {
+	ids := make([]K, len(g.sorted))
+	for i, v := range g.sorted {
+		ids[len(ids)-1-i] = g.ids[v]
+	}
+	return ids
+}
+
+// PostOrder returns the keys of the graph in depth-first post-order of their
+// dependencies: starting from each leaf in topological order, the keys a
+// key depends on are emitted right before it, like make builds targets. It's
+// also a valid topological order, where the dependencies of each leaf stay
+// as close together as possible.
+func (g *Graph[K]) PostOrder() []K {
+	type frame struct {
+		v    int // vertex being visited
+		next int // index of the next dependency to follow
+	}
+
+	visited := make([]bool, len(g.ids))
+	order := make([]int, 0, len(g.sorted))
+	for _, leaf := range g.sorted {
+		if len(g.afters[leaf]) > 0 || visited[leaf] {
+			continue
+		}
+		visited[leaf] = true
+		stack := []frame{{v: leaf}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.next < len(g.befores[top.v]) {
+				before := g.befores[top.v][top.next]
+				top.next++
+				if !visited[before] {
+					visited[before] = true
+					stack = append(stack, frame{v: before})
+				}
+				continue
+			}
+			order = append(order, top.v)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return g.keys(order)
+}
+
+// Warnings returns the validation errors downgraded to warnings by
+// WithWarnings when the graph was built, or nil if there are none.
+func (g *Graph[K]) Warnings() []error {
+	if len(g.warnings) == 0 {
+		return nil
+	}
+	return append([]error{}, g.warnings...)
+}
+
+// Iter returns an iterator yielding the keys of the graph in topological
+// order, without allocating them all like SortedIDs. It has the signature
+// of an iter.Seq[K], so that it can be ranged over with Go 1.23 or later:
+//
+//	for id := range g.Iter() {
+//		...
+//	}
+//
+// The graph must not be mutated while iterating.
+func (g *Graph[K]) Iter() func(yield func(K) bool) {
+	return func(yield func(K) bool) {
+		for _, v := range g.sorted {
+			if !yield(g.ids[v]) {
+				return
+			}
+		}
+	}
+}
+
+// Index returns the position of id in the topological order, or -1 if id is
+// not in the graph.
+func (g *Graph[K]) Index(id K) int {
+	if v, ok := g.lookup(id); ok {
+		return g.position[v]
+	}
+	return -1
+}
+
+// Before reports whether a precedes b in the topological order. It returns
+// false if either of them is not in the graph.
+func (g *Graph[K]) Before(a, b K) bool {
+	i, j := g.Index(a), g.Index(b)
+	return i >= 0 && j >= 0 && i < j
+}
+
+// SortSlice reorders items in place according to the topological order of
+// the graph. It returns an error wrapping ErrNotFound, leaving items as they
+// are, if any of them is not in the graph.
+func (g *Graph[K]) SortSlice(items []K) error {
+	return SortSliceFunc(g, items, func(id K) K { return id })
+}
+
+// SortSliceFunc reorders items in place according to the topological order
+// of the keys returned by key. Items with the same key keep their original
+// order. It returns an error wrapping ErrNotFound, leaving items as they are,
+// if any key is not in the graph.
+func SortSliceFunc[T any, K comparable](g *Graph[K], items []T, key func(T) K) error {
+	for _, item := range items {
+		if id := key(item); g.Index(id) < 0 {
+			return fmt.Errorf("%w: %v", ErrNotFound, id)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return g.Index(key(items[i])) < g.Index(key(items[j]))
+	})
+
+	return nil
+}
+
+// Sort sorts relations topologically, where each key depends on its
+// corresponding value. See NewGraph for the errors it returns.
+func Sort[K comparable](relations map[K]K, opts ...Option) ([]K, error) {
+	g, err := NewGraph(relations, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.SortedIDs(), nil
+}
+
+// Validate checks relations like NewGraph, without keeping a graph nor
+// sorting it in any particular order, so that linters only interested in the
+// errors take a cheaper path. Unlike NewGraph, it doesn't stop at invalid
+// names, and returns a MultiError of all the errors found, leaving out those
+// downgraded to warnings by WithWarnings.
+func Validate[K comparable](relations map[K]K, opts ...Option) error {
+	ids, edges := relationEdges(relations)
+
+	g, err := build(ids, edges, newOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	errs := validateNames(g.ids, g.opts.validators, g.spelling)
+	if g.sorted, g.cycles, err = tsort(context.Background(), g.afters); err != nil {
+		return err
+	}
+	g.markRecursive()
+	errs = append(errs, validateGraph(context.Background(), g)...)
+
+	if errs, _ = g.opts.split(errs); errs == nil {
+		return nil
+	}
+	return errs
+}
+
+// SortItems returns items sorted topologically, where id returns the key of
+// an item and deps the keys of the items it depends on, so that a slice of
+// values knowing their own dependencies can be ordered in one call. Items
+// sharing a key keep their original order.
+//
+// It returns an error wrapping ErrNotFound if a dependency is not the key of
+// any item. See NewGraph for the other errors it returns.
+func SortItems[T any, K comparable](items []T, deps func(T) []K, id func(T) K, opts ...Option) ([]T, error) {
+	known := make(map[K]bool, len(items))
+	ids := make([]K, len(items))
+	for i, item := range items {
+		ids[i] = id(item)
+		known[ids[i]] = true
+	}
+
+	edges := [][2]K{}
+	for i, item := range items {
+		for _, dep := range deps(item) {
+			if !known[dep] {
+				return nil, fmt.Errorf("%w: %v, dependency of %v", ErrNotFound, dep, ids[i])
+			}
+			edges = append(edges, [2]K{dep, ids[i]})
+		}
+	}
+
+	g, err := newGraph(context.Background(), ids, edges, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]T{}, items...)
+	if err := SortSliceFunc(g, sorted, id); err != nil {
+		return nil, err
+	}
+
+	return sorted, nil
+}
+
+// validateNames checks every key with validators, which are expected to be
+// func(K) error, reporting the first error of each key by its spelling.
+func validateNames[K comparable](ids []K, validators []any, spelling func(K) K) (err MultiError) {
+	fns := make([]func(K) error, len(validators))
+	for i, validate := range validators {
+		fn, ok := validate.(func(K) error)
+		if !ok {
+			return MultiError{fmt.Errorf("%w: validator %T does not accept %T keys", ErrInvalidName, validate, *new(K))}
+		}
+		fns[i] = fn
+	}
+
+	for _, id := range ids {
+		for _, fn := range fns {
+			if e := fn(id); e != nil {
+				err = append(err, fmt.Errorf("%w %v: %v", ErrInvalidName, spelling(id), e))
+				break
+			}
+		}
 	}
 
 	return
 }
 
-func sliceContains[K comparable](s []K, e K) bool {
-	for _, a := range s {
-		if a == e {
-			return true
+// cycleErrors returns an error for each cycle found while sorting g.
+func (g *Graph[K]) cycleErrors() (err MultiError) {
+	for _, path := range g.cycles {
+		if len(path) == 2 {
+			err = append(err, &SelfReferenceError[K]{ID: g.spelling(g.ids[path[0]])})
+		} else {
+			err = append(err, &CycleError[K]{Path: g.spellings(path)})
 		}
 	}
-	return false
+	return
+}
+
+// validateGraph checks a graph for recursive paths and multiple root nodes.
+//
+// It stops early when ctx is done, leaving it to the caller to check.
+func validateGraph[K comparable](ctx context.Context, g *Graph[K]) (err MultiError) {
+	// roots are the keys without dependencies
+	roots := []K{}
+	for i, v := range g.sorted {
+		if i%cancelCheckInterval == 0 && ctx.Err() != nil {
+			return
+		}
+		if len(g.befores[v]) == 0 {
+			roots = append(roots, g.spelling(g.ids[v]))
+		}
+	}
+
+	// add all cyclic dependency errors to the multierror instance
+	err = append(err, g.cycleErrors()...)
+
+	// add multiple roots error after that if found any
+	if len(roots) > 1 && !g.opts.allowMultipleRoots {
+		err = append(err, &MultipleRootsError[K]{Roots: roots})
+	}
+
+	// a graph made of several components is rejected on request, whether
+	// they are trees of a forest or not
+	if g.opts.connected {
+		if components := g.Components(); len(components) > 1 {
+			for _, keys := range components {
+				for i, id := range keys {
+					keys[i] = g.spelling(id)
+				}
+			}
+			err = append(err, &DisconnectedError[K]{Components: components})
+		}
+	}
+
+	// and finally the chain error, only meaningful without cycles
+	if g.opts.strictChain && len(g.cycles) == 0 {
+		if e := g.chainError(); e != nil {
+			err = append(err, e)
+		}
+	}
+
+	return
 }
@@ -1,8 +1,12 @@
 package toposort_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"runtime/debug"
+	"strings"
 	"testing"
 
 	"github.com/onur1/toposort"
@@ -93,3 +97,461 @@ func TestGraph(t *testing.T) {
 		})
 	}
 }
+
+func TestGraphDeepChain(t *testing.T) {
+	const depth = 200000
+
+	// a recursive traversal would need several times this much stack for
+	// such a chain
+	defer debug.SetMaxStack(debug.SetMaxStack(8 << 20))
+
+	data := make(map[int]int, depth)
+	for i := 1; i < depth; i++ {
+		data[i] = i - 1
+	}
+
+	sorted, err := toposort.Sort(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sorted) != depth {
+		t.Fatalf("expected %d sorted keys, got %d", depth, len(sorted))
+	}
+	for i, k := range sorted {
+		if k != i {
+			t.Fatalf("expected key %d at index %d, got %d", i, i, k)
+		}
+	}
+}
+
+func TestCycleError(t *testing.T) {
+	_, err := toposort.Sort(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Barbara",
+		"Jonas":   "Jonas",
+	})
+
+	var paths [][]string
+	for _, e := range err.(toposort.MultiError) {
+		var ce *toposort.CycleError[string]
+		if errors.As(e, &ce) {
+			paths = append(paths, ce.Path)
+		}
+	}
+	if len(paths) != 1 {
+		t.Fatalf("expected 1 cycle, got %v", paths)
+	}
+	if path := paths[0]; len(path) != 4 || path[0] != path[3] {
+		t.Fatalf("unexpected cycle path %v", path)
+	}
+
+	var ce *toposort.CycleError[string]
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected %v to contain a cycle error", err)
+	}
+}
+
+func TestMultipleRootsError(t *testing.T) {
+	_, err := toposort.Sort(map[string]string{
+		"Barbara": "Nick",
+		"Ruby":    "Daniel",
+	})
+
+	var re *toposort.MultipleRootsError[string]
+	if !errors.As(err, &re) {
+		t.Fatalf("expected %v to contain a multiple roots error", err)
+	}
+	roots := map[string]bool{}
+	for _, id := range re.Roots {
+		roots[id] = true
+	}
+	if !reflect.DeepEqual(roots, map[string]bool{"Nick": true, "Daniel": true}) {
+		t.Fatalf("unexpected roots %v", re.Roots)
+	}
+}
+
+func TestGraphSingleRoot(t *testing.T) {
+	// every key but the root has a single dependency, so the graph is a tree
+	data := make(map[int]int, 1000)
+	for i := 1; i < 1000; i++ {
+		data[i] = (i - 1) / 2
+	}
+
+	g, err := toposort.NewGraph(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roots := g.Roots(); !reflect.DeepEqual(roots, []int{0}) {
+		t.Fatalf("expected a single root, got %v", roots)
+	}
+
+	if _, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d;
+	a -> c -> d;
+}`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGraphAllowMultipleRoots(t *testing.T) {
+	data := map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Ruby":    "Daniel",
+	}
+
+	g, err := toposort.NewGraph(data, toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sorted := g.SortedIDs()
+	if len(sorted) != 5 {
+		t.Fatalf("expected 5 sorted keys, got %v", sorted)
+	}
+	index := make(map[string]int)
+	for i, id := range sorted {
+		index[id] = i
+	}
+	for c, p := range data {
+		if index[p] > index[c] {
+			t.Fatalf("expected %s to come before %s in %v", p, c, sorted)
+		}
+	}
+}
+
+func TestNewGraphFromPairs(t *testing.T) {
+	ids := []string{"Ruby", "Barbara"}
+	pairs := [][2]string{{"Nick", "Barbara"}, {"Sophie", "Nick"}}
+
+	g, err := toposort.NewGraphFromPairs(ids, pairs, toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted := g.SortedIDs()
+	if len(sorted) != 4 || g.Index("Ruby") < 0 {
+		t.Fatalf("expected 4 sorted keys, got %v", sorted)
+	}
+	if g.Index("Sophie") > g.Index("Nick") || g.Index("Nick") > g.Index("Barbara") {
+		t.Fatalf("expected Sophie, Nick and Barbara in order, got %v", sorted)
+	}
+}
+
+func TestGraphIter(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	g.Iter()(func(id string) bool {
+		ids = append(ids, id)
+		return true
+	})
+	if !reflect.DeepEqual(ids, g.SortedIDs()) {
+		t.Fatalf("expected keys %v != %v", g.SortedIDs(), ids)
+	}
+
+	ids = ids[:0]
+	g.Iter()(func(id string) bool {
+		ids = append(ids, id)
+		return id != "Sophie"
+	})
+	if expected := []string{"Jonas", "Sophie"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected iteration to stop early at %v, got %v", expected, ids)
+	}
+}
+
+func TestGraphSortedIDsReverse(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	x -> y;
+	a -> b -> c;
+	a -> c;
+}`), toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"y", "x", "c", "b", "a"}; !reflect.DeepEqual(g.SortedIDsReverse(), expected) {
+		t.Fatalf("expected reverse order %v != %v", expected, g.SortedIDsReverse())
+	}
+	if expected := []string{"a", "b", "c", "x", "y"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected the sorted keys to be left unchanged, got %v", g.SortedIDs())
+	}
+	if expected := []string{"a", "b", "c", "x", "y"}; !reflect.DeepEqual(g.PostOrder(), expected) {
+		t.Fatalf("expected post-order %v != %v", expected, g.PostOrder())
+	}
+
+	g, err = toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> p;
+	b -> q;
+	a -> q;
+}`), toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a", "p", "b", "q"}; !reflect.DeepEqual(g.PostOrder(), expected) {
+		t.Fatalf("expected post-order %v != %v", expected, g.PostOrder())
+	}
+}
+
+func TestGraphIndexBefore(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, id := range g.SortedIDs() {
+		if j := g.Index(id); j != i {
+			t.Fatalf("expected Index(%s) %d != %d", id, i, j)
+		}
+	}
+	if i := g.Index("Ruby"); i != -1 {
+		t.Fatalf("expected Index(Ruby) -1 != %d", i)
+	}
+
+	testCases := []struct {
+		a, b   string
+		before bool
+	}{
+		{"Jonas", "Barbara", true},
+		{"Barbara", "Jonas", false},
+		{"Nick", "Nick", false},
+		{"Ruby", "Jonas", false},
+		{"Jonas", "Ruby", false},
+	}
+	for _, tt := range testCases {
+		if before := g.Before(tt.a, tt.b); before != tt.before {
+			t.Fatalf("expected Before(%s, %s) %v != %v", tt.a, tt.b, tt.before, before)
+		}
+	}
+}
+
+func TestGraphSortSlice(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []string{"Barbara", "Jonas", "Nick"}
+	if err := g.SortSlice(items); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Jonas", "Nick", "Barbara"}; !reflect.DeepEqual(items, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, items)
+	}
+
+	type task struct {
+		name string
+		n    int
+	}
+	tasks := []task{{"Nick", 1}, {"Sophie", 2}, {"Nick", 3}}
+	if err := toposort.SortSliceFunc(g, tasks, func(t task) string { return t.name }); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []task{{"Sophie", 2}, {"Nick", 1}, {"Nick", 3}}; !reflect.DeepEqual(tasks, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, tasks)
+	}
+
+	items = []string{"Nick", "Ruby"}
+	if err := g.SortSlice(items); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+}
+
+func TestSelfReferenceError(t *testing.T) {
+	_, err := toposort.Sort(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Nick",
+	})
+
+	var se *toposort.SelfReferenceError[string]
+	if !errors.As(err, &se) {
+		t.Fatalf("expected %v to contain a self reference error", err)
+	}
+	if se.ID != "Nick" {
+		t.Fatalf("expected self reference of Nick, got %v", se.ID)
+	}
+	if !errors.Is(err, toposort.ErrSelfReference) || !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected %v to match both %v and %v", err, toposort.ErrSelfReference, toposort.ErrCircular)
+	}
+
+	var ce *toposort.CycleError[string]
+	if errors.As(err, &ce) {
+		t.Fatalf("unexpected cycle error %v", ce)
+	}
+}
+
+func TestErrorsUseKeysAsGiven(t *testing.T) {
+	_, err := toposort.Sort(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Barbara",
+		"Sophie":  "Jonas",
+		"Ruby":    "Daniel",
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, e := range err.(toposort.MultiError) {
+		msg := e.Error()
+		for _, id := range []string{"barbara", "nick", "jonas", "daniel"} {
+			if strings.Contains(msg, id) {
+				t.Fatalf("expected %q to print keys as given", msg)
+			}
+		}
+	}
+}
+
+func TestNewGraphContext(t *testing.T) {
+	data := map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	}
+
+	g, err := toposort.NewGraphContext(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.TransitiveClosureContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := toposort.NewGraphContext(ctx, data); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error %v != %v", context.Canceled, err)
+	}
+	if _, err := g.TransitiveClosureContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error %v != %v", context.Canceled, err)
+	}
+}
+
+func BenchmarkNewGraph(b *testing.B) {
+	data := make(map[string]string, 10000)
+	for i := 1; i < 10000; i++ {
+		data[fmt.Sprintf("k%d", i)] = fmt.Sprintf("k%d", (i-1)/2)
+	}
+
+	benchmarks := []struct {
+		desc string
+		opts []toposort.Option
+	}{
+		{"validated", []toposort.Option{toposort.WithAllowMultipleRoots()}},
+		{"without validation", []toposort.Option{toposort.WithoutValidation()}},
+	}
+	for _, bb := range benchmarks {
+		bb := bb
+
+		b.Run(bb.desc, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := toposort.NewGraph(data, bb.opts...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	type migration struct {
+		name string
+		deps []string
+	}
+
+	migrations := []migration{
+		{"add_index", []string{"create_users", "add_email"}},
+		{"add_email", []string{"create_users"}},
+		{"create_users", nil},
+		{"create_posts", []string{"create_users"}},
+	}
+	deps := func(m migration) []string { return m.deps }
+	name := func(m migration) string { return m.name }
+
+	sorted, err := toposort.SortItems(migrations, deps, name, toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, m := range sorted {
+		names = append(names, m.name)
+	}
+	if expected := []string{"create_users", "add_email", "add_index", "create_posts"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, names)
+	}
+	if migrations[0].name != "add_index" {
+		t.Fatal("expected items to be left as they are")
+	}
+
+	migrations = append(migrations, migration{"drop_users", []string{"create_accounts"}})
+	if _, err := toposort.SortItems(migrations, deps, name); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := toposort.Validate(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := toposort.Validate(map[string]string{
+		"b":  "a",
+		"a":  "b",
+		"x!": "y",
+	}, toposort.WithAllowedRunes(toposort.Alphanumeric))
+	if !errors.Is(err, toposort.ErrInvalidName) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidName, err)
+	}
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+}
+
+func TestNewGraphEmptyParent(t *testing.T) {
+	relations := map[string]string{
+		"Jonas":   "",
+		"Sophie":  "Jonas",
+		"Nick":    "Sophie",
+		"Barbara": "Nick",
+	}
+
+	g, err := toposort.NewGraph(relations, toposort.WithMinIDLength(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Jonas", "Sophie", "Nick", "Barbara"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, g.SortedIDs())
+	}
+
+	relations["Ruby"] = ""
+	if err := toposort.Validate(relations); !errors.Is(err, toposort.ErrMultipleRoots) {
+		t.Fatalf("expected error %v != %v", toposort.ErrMultipleRoots, err)
+	}
+	g, err = toposort.NewGraph(relations, toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Jonas", "Ruby"}; !reflect.DeepEqual(g.Roots(), expected) {
+		t.Fatalf("expected roots %v != %v", expected, g.Roots())
+	}
+}
@@ -0,0 +1,112 @@
+package toposort
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WriteGraphML writes the graph to w in GraphML format, which tools like yEd
+// and Gephi load along with the data attached to vertices and edges.
+//
+// Vertices are written in topological order, identified by their keys, with
+// their attributes and weights, and edges point from each key to the keys
+// depending on it, with their labels. Attributes of booleans, integers and
+// floats keep their type, while any other value is written as a string.
+func (g *Graph[K]) WriteGraphML(w io.Writer) error {
+	// attribute names and their GraphML types, string if mixed
+	types := map[string]string{}
+	for _, attrs := range g.attrs {
+		for name, value := range attrs {
+			t := graphMLType(value)
+			if prev, ok := types[name]; ok && prev != t {
+				t = "string"
+			}
+			types[name] = t
+		}
+	}
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	for i, name := range names {
+		fmt.Fprintf(&b, "\t<key id=\"d%d\" for=\"node\" attr.name=%s attr.type=%q/>\n", i, xmlAttr(name), types[name])
+	}
+	if g.weights != nil {
+		b.WriteString("\t<key id=\"weight\" for=\"node\" attr.name=\"weight\" attr.type=\"double\"/>\n")
+	}
+	if len(g.labels) > 0 {
+		b.WriteString("\t<key id=\"label\" for=\"edge\" attr.name=\"label\" attr.type=\"string\"/>\n")
+	}
+	b.WriteString("\t<graph edgedefault=\"directed\">\n")
+
+	for _, v := range g.sorted {
+		fmt.Fprintf(&b, "\t\t<node id=%s", xmlAttr(fmt.Sprint(g.ids[v])))
+		var attrs map[string]any
+		if g.attrs != nil {
+			attrs = g.attrs[v]
+		}
+		if len(attrs) == 0 && g.weights == nil {
+			b.WriteString("/>\n")
+			continue
+		}
+		b.WriteString(">\n")
+		for i, name := range names {
+			if value, ok := attrs[name]; ok {
+				fmt.Fprintf(&b, "\t\t\t<data key=\"d%d\">%s</data>\n", i, xmlText(fmt.Sprint(value)))
+			}
+		}
+		if g.weights != nil {
+			fmt.Fprintf(&b, "\t\t\t<data key=\"weight\">%v</data>\n", g.weights[v])
+		}
+		b.WriteString("\t\t</node>\n")
+	}
+	for _, v := range g.sorted {
+		for _, after := range g.afters[v] {
+			fmt.Fprintf(&b, "\t\t<edge source=%s target=%s", xmlAttr(fmt.Sprint(g.ids[v])), xmlAttr(fmt.Sprint(g.ids[after])))
+			if label := g.labels[[2]K{g.ids[v], g.ids[after]}]; label != "" {
+				fmt.Fprintf(&b, ">\n\t\t\t<data key=\"label\">%s</data>\n\t\t</edge>\n", xmlText(label))
+			} else {
+				b.WriteString("/>\n")
+			}
+		}
+	}
+	b.WriteString("\t</graph>\n</graphml>\n")
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+}
+
+// graphMLType returns the GraphML type of an attribute value.
+func graphMLType(value any) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "long"
+	case float32, float64:
+		return "double"
+	}
+	return "string"
+}
+
+// xmlText escapes s for the content of an XML element.
+func xmlText(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// xmlAttr formats s as a quoted XML attribute value.
+func xmlAttr(s string) string {
+	return `"` + xmlText(s) + `"`
+}
@@ -0,0 +1,62 @@
+package toposort_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphWriteGraphML(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Nick":   "Sophie",
+		"Sophie": "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetAttr("Nick", "age", 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetAttr("Sophie", "city", "<Paris>"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetEdgeLabel("Sophie", "Nick", "mentor"); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := g.WriteGraphML(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="d0" for="node" attr.name="age" attr.type="long"/>
+	<key id="d1" for="node" attr.name="city" attr.type="string"/>
+	<key id="label" for="edge" attr.name="label" attr.type="string"/>
+	<graph edgedefault="directed">
+		<node id="Jonas"/>
+		<node id="Sophie">
+			<data key="d1">&lt;Paris&gt;</data>
+		</node>
+		<node id="Nick">
+			<data key="d0">7</data>
+		</node>
+		<edge source="Jonas" target="Sophie"/>
+		<edge source="Sophie" target="Nick">
+			<data key="label">mentor</data>
+		</edge>
+	</graph>
+</graphml>
+`
+	if b.String() != expected {
+		t.Fatalf("expected GraphML output %q != %q", expected, b.String())
+	}
+
+	var v struct{}
+	if err := xml.Unmarshal(b.Bytes(), &v); err != nil {
+		t.Fatalf("expected well-formed XML, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package toposort
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// errInvalidOrder reports an encoded order that doesn't sort the encoded
+// graph.
+var errInvalidOrder = errors.New("encoded order is not a topological order")
+
+// jsonGraph is the JSON representation of a graph. Vertices are only
+// decoded, for encodings listing the keys apart from the order.
+type jsonGraph[K comparable] struct {
+	Vertices []K    `json:"vertices,omitempty"`
+	Edges    [][2]K `json:"edges"`
+	Sorted   []K    `json:"sorted"`
+}
+
+// MarshalJSON implements json.Marshaler. The graph is encoded as an object
+// holding its edges as [from, to] pairs pointing from a key to a key
+// depending on it, and its topological order, which lists every key,
+// including the keys without edges.
+func (g *Graph[K]) MarshalJSON() ([]byte, error) {
+	v := jsonGraph[K]{
+		Edges:  g.Edges(),
+		Sorted: g.SortedIDs(),
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The graph is rebuilt from the
+// decoded keys and edges, and checked for cycles, but may have several
+// roots. The encoded order is kept if it's a valid order of the graph, and
+// computed again if it's missing. A list of "vertices" is accepted as well,
+// for keys without edges missing from the order.
+func (g *Graph[K]) UnmarshalJSON(data []byte) error {
+	var v jsonGraph[K]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	ids := append(v.Vertices, v.Sorted...)
+	ng, err := newGraph(context.Background(), ids, v.Edges, options{}.relaxed())
+	if err != nil {
+		return err
+	}
+	if v.Sorted != nil {
+		if !ng.IsValidOrder(v.Sorted) {
+			return errInvalidOrder
+		}
+		for i, id := range v.Sorted {
+			ng.sorted[i] = ng.vertex[id]
+			ng.position[ng.sorted[i]] = i
+		}
+		ng.invalidate()
+	}
+
+	*g = *ng
+
+	return nil
+}
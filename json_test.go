@@ -0,0 +1,93 @@
+package toposort_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphJSON(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"edges":[["Jonas","Sophie"],["Sophie","Nick"],["Nick","Barbara"]],` +
+		`"sorted":["Jonas","Sophie","Nick","Barbara"]}`
+	if string(data) != expected {
+		t.Fatalf("expected JSON %s != %s", expected, data)
+	}
+
+	var loaded toposort.Graph[string]
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.SortedIDs(), g.SortedIDs()) {
+		t.Fatalf("expected sorted value %+v != %+v", g.SortedIDs(), loaded.SortedIDs())
+	}
+
+	err = json.Unmarshal([]byte(`{"edges":[["a","b"],["b","a"]]}`), &loaded)
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+
+	err = json.Unmarshal([]byte(`{"edges":[["a","b"]],"sorted":["b","a"]}`), &loaded)
+	if err == nil {
+		t.Fatal("expected an error for an invalid order")
+	}
+}
+
+func TestGraphJSONForest(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"a": "b",
+		"c": "d",
+		"e": "d",
+	}, toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded toposort.Graph[string]
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.SortedIDs(), g.SortedIDs()) {
+		t.Fatalf("expected sorted value %+v != %+v", g.SortedIDs(), loaded.SortedIDs())
+	}
+	if !reflect.DeepEqual(loaded.Edges(), g.Edges()) {
+		t.Fatalf("expected edges %v != %v", g.Edges(), loaded.Edges())
+	}
+
+	data = []byte(`{"vertices":["a","b","c"],"edges":[["a","b"]],"sorted":["c","a","b"]}`)
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if sorted := loaded.SortedIDs(); !reflect.DeepEqual(sorted, []string{"c", "a", "b"}) {
+		t.Fatalf("expected the encoded order, got %+v", sorted)
+	}
+
+	data = []byte(`{"edges":[["a","b"]],"sorted":["c","a","b"]}`)
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if sorted := loaded.SortedIDs(); !reflect.DeepEqual(sorted, []string{"c", "a", "b"}) {
+		t.Fatalf("expected the keys of the order, got %+v", sorted)
+	}
+}
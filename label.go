@@ -0,0 +1,180 @@
+package toposort
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Edge is an edge of a graph, where To depends on From, with an optional
+// label telling the kind of dependency, like "hard" or "soft".
+type Edge[K comparable] struct {
+	From, To K
+	Label    string
+}
+
+// NewGraphFromEdges builds a graph from labeled edges, where the To key of
+// each edge depends on its From key, and sorts it topologically. Edges with
+// a label ignored by WithIgnoredLabels are left out of the graph, keeping
+// their keys, and edges with a label made optional by WithOptionalLabels are
+// left out only if they would close a cycle. See NewGraph for the errors it
+// returns.
+func NewGraphFromEdges[K comparable](edges []Edge[K], opts ...Option) (*Graph[K], error) {
+	o := newOptions(opts)
+
+	ignored := make(map[string]bool, len(o.ignoredLabels))
+	for _, label := range o.ignoredLabels {
+		ignored[label] = true
+	}
+	optional := make(map[string]bool, len(o.optionalLabels))
+	for _, label := range o.optionalLabels {
+		optional[label] = true
+	}
+
+	ids := []K{}
+	pairs := make([][2]K, 0, len(edges))
+	soft := []Edge[K]{}
+	for _, e := range edges {
+		switch {
+		case ignored[e.Label]:
+			ids = append(ids, e.From, e.To)
+			o.debug("edge ignored", "from", e.From, "to", e.To, "label", e.Label)
+		case optional[e.Label]:
+			ids = append(ids, e.From, e.To)
+			soft = append(soft, e)
+		default:
+			pairs = append(pairs, [2]K{e.From, e.To})
+		}
+	}
+
+	var dropped []Edge[K]
+	if len(soft) > 0 {
+		kept, err := acyclic(ids, pairs, soft, o)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range soft {
+			if kept[[2]K{e.From, e.To}] {
+				pairs = append(pairs, [2]K{e.From, e.To})
+			} else {
+				dropped = append(dropped, e)
+				o.debug("optional edge dropped to avoid a cycle", "from", e.From, "to", e.To, "label", e.Label)
+			}
+		}
+	}
+
+	g, err := newGraph(context.Background(), ids, pairs, o)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range edges {
+		if e.Label != "" && !ignored[e.Label] {
+			g.SetEdgeLabel(e.From, e.To, e.Label)
+		}
+	}
+	for i, e := range dropped {
+		dropped[i].From, dropped[i].To = g.canonical(e.From), g.canonical(e.To)
+	}
+	g.dropped = append(dropped, g.dropped...)
+
+	return g, nil
+}
+
+// acyclic adds the optional edges one by one to the graph made of the given
+// keys and edges, and returns the optional edges that don't close a cycle.
+// If the graph is cyclic already, none of them is kept, so that only its
+// own cycles are reported.
+func acyclic[K comparable](ids []K, edges [][2]K, optional []Edge[K], o options) (map[[2]K]bool, error) {
+	g, err := build(ids, edges, o)
+	if err != nil {
+		return nil, err
+	}
+	if g.sorted, g.cycles, err = tsort(context.Background(), g.afters); err != nil {
+		return nil, err
+	}
+	kept := map[[2]K]bool{}
+	if len(g.cycles) > 0 {
+		return kept, nil
+	}
+	g.position = make([]int, len(g.ids))
+	for i, v := range g.sorted {
+		g.position[v] = i
+	}
+	g.recursive = make([]bool, len(g.ids))
+	g.invalidate()
+
+	for _, e := range optional {
+		err := g.AddEdge(e.From, e.To)
+		if errors.Is(err, ErrCircular) || errors.Is(err, ErrSelfReference) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		kept[[2]K{e.From, e.To}] = true
+	}
+
+	return kept, nil
+}
+
+// DroppedEdges returns the edges left out of the graph to break cycles, the
+// optional edges of WithOptionalLabels first, then the edges dropped by the
+// cycle policy, or nil if there are none.
+func (g *Graph[K]) DroppedEdges() []Edge[K] {
+	if len(g.dropped) == 0 {
+		return nil
+	}
+	return append([]Edge[K]{}, g.dropped...)
+}
+
+// SetEdgeLabel sets the label of the edge from from to to. It returns an
+// error wrapping ErrNotFound if there's no such edge.
+//
+// Labels are copied to the graphs derived from g, for the edges they keep.
+// SetEdgeLabel must not be called concurrently with other methods of g.
+func (g *Graph[K]) SetEdgeLabel(from, to K, label string) error {
+	from, to = g.canonical(from), g.canonical(to)
+	if !g.hasEdge(from, to) {
+		return fmt.Errorf("%w: edge %v -> %v", ErrNotFound, from, to)
+	}
+	if g.labels == nil {
+		g.labels = make(map[[2]K]string)
+	}
+	if label == "" {
+		delete(g.labels, [2]K{from, to})
+	} else {
+		g.labels[[2]K{from, to}] = label
+	}
+
+	return nil
+}
+
+// EdgeLabel returns the label of the edge from from to to, and whether
+// there's such an edge. Edges without a label have an empty one.
+func (g *Graph[K]) EdgeLabel(from, to K) (string, bool) {
+	from, to = g.canonical(from), g.canonical(to)
+	if !g.hasEdge(from, to) {
+		return "", false
+	}
+	return g.labels[[2]K{from, to}], true
+}
+
+// LabeledEdges returns the edges of the graph with their labels, in the
+// order of Edges.
+func (g *Graph[K]) LabeledEdges() []Edge[K] {
+	edges := []Edge[K]{}
+	for _, e := range g.Edges() {
+		edges = append(edges, Edge[K]{From: e[0], To: e[1], Label: g.labels[e]})
+	}
+	return edges
+}
+
+// hasEdge reports whether there's an edge from from to to.
+func (g *Graph[K]) hasEdge(from, to K) bool {
+	x, ok := g.vertex[from]
+	if !ok {
+		return false
+	}
+	y, ok := g.vertex[to]
+	return ok && contains(g.afters[x], y)
+}
@@ -0,0 +1,94 @@
+package toposort_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestNewGraphFromEdges(t *testing.T) {
+	edges := []toposort.Edge[string]{
+		{From: "libc", To: "bash", Label: "hard"},
+		{From: "bash", To: "libc", Label: "soft"},
+		{From: "bash", To: "vim"},
+	}
+
+	if _, err := toposort.NewGraphFromEdges(edges); !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+
+	g, err := toposort.NewGraphFromEdges(edges, toposort.WithIgnoredLabels("soft"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"libc", "bash", "vim"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, g.SortedIDs())
+	}
+	expected := []toposort.Edge[string]{
+		{From: "libc", To: "bash", Label: "hard"},
+		{From: "bash", To: "vim"},
+	}
+	if labeled := g.LabeledEdges(); !reflect.DeepEqual(labeled, expected) {
+		t.Fatalf("expected edges %v != %v", expected, labeled)
+	}
+
+	if err := g.SetEdgeLabel("bash", "vim", "optional"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetEdgeLabel("vim", "bash", "hard"); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+	if label, ok := g.Clone().EdgeLabel("bash", "vim"); label != "optional" || !ok {
+		t.Fatalf("expected the label to be cloned, got %q (%t)", label, ok)
+	}
+
+	var b bytes.Buffer
+	if err := g.DOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `"bash" -> "vim" [label="optional"];`; !strings.Contains(b.String(), expected) {
+		t.Fatalf("expected %q in %s", expected, b.String())
+	}
+
+	if err := g.RemoveVertex("vim"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdge("bash", "vim"); err != nil {
+		t.Fatal(err)
+	}
+	if label, ok := g.EdgeLabel("bash", "vim"); label != "" || !ok {
+		t.Fatalf("expected the label to be removed with the edge, got %q (%t)", label, ok)
+	}
+}
+
+func TestNewGraphFromEdgesOptional(t *testing.T) {
+	edges := []toposort.Edge[string]{
+		{From: "network", To: "sshd", Label: "requires"},
+		{From: "sshd", To: "network", Label: "wants"},
+		{From: "syslog", To: "sshd", Label: "wants"},
+		{From: "sshd", To: "sshd", Label: "wants"},
+	}
+
+	g, err := toposort.NewGraphFromEdges(edges, toposort.WithOptionalLabels("wants"), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []toposort.Edge[string]{edges[1], edges[3]}; !reflect.DeepEqual(g.DroppedEdges(), expected) {
+		t.Fatalf("expected dropped edges %v != %v", expected, g.DroppedEdges())
+	}
+	if label, ok := g.EdgeLabel("syslog", "sshd"); label != "wants" || !ok {
+		t.Fatalf("expected the optional edge to be kept, got %q (%t)", label, ok)
+	}
+	if !reflect.DeepEqual(g.Clone().DroppedEdges(), g.DroppedEdges()) {
+		t.Fatal("expected the dropped edges to be cloned")
+	}
+
+	edges = append(edges, toposort.Edge[string]{From: "sshd", To: "network", Label: "requires"})
+	if _, err := toposort.NewGraphFromEdges(edges, toposort.WithOptionalLabels("wants")); !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+}
@@ -0,0 +1,197 @@
+package toposort
+
+// Layers groups the keys of the graph into levels, such that every key only
+// depends on keys in earlier levels. Keys in the same level don't depend on
+// each other, so they can be processed in parallel.
+//
+// Keys within a level keep their topological order.
+func (g *Graph[K]) Layers() [][]K {
+	depths, n := g.depths()
+
+	layers := make([][]K, n)
+	for _, v := range g.sorted {
+		layers[depths[v]] = append(layers[depths[v]], g.ids[v])
+	}
+
+	return layers
+}
+
+// Depth returns the length of the longest dependency chain leading to id,
+// which is 0 for a root, or -1 if id is not in the graph.
+func (g *Graph[K]) Depth(id K) int {
+	v, ok := g.lookup(id)
+	if !ok {
+		return -1
+	}
+	depths, _ := g.depths()
+	return depths[v]
+}
+
+// Height returns the length of the longest dependency chain starting from
+// id, which is 0 for a leaf, or -1 if id is not in the graph.
+func (g *Graph[K]) Height(id K) int {
+	v, ok := g.lookup(id)
+	if !ok {
+		return -1
+	}
+	return g.heights()[v]
+}
+
+// CriticalPath returns the heaviest dependency chain of the graph, from a
+// root to a leaf, whose weight is the sum of the weights of its keys. Without
+// weights set, that's the longest chain. If there are several, the one
+// ending first in the topological order is returned.
+func (g *Graph[K]) CriticalPath() []K {
+	path, _ := g.criticalPath()
+	return g.keys(path)
+}
+
+// CriticalPathWeight returns the weight of the critical path, like the
+// minimum duration of a build running its steps in parallel.
+func (g *Graph[K]) CriticalPathWeight() float64 {
+	_, w := g.criticalPath()
+	return w
+}
+
+// criticalPath returns the vertices of the critical path and its weight.
+func (g *Graph[K]) criticalPath() ([]int, float64) {
+	if len(g.sorted) == 0 {
+		return []int{}, 0
+	}
+
+	weights, prev := g.heaviestChains()
+	end := g.sorted[0]
+	for _, v := range g.sorted {
+		if weights[v] > weights[end] {
+			end = v
+		}
+	}
+
+	return chainTo(prev, end), weights[end]
+}
+
+// heaviestChains returns the weight of the heaviest chain leading to each
+// vertex, and the vertex before it on that chain, or -1 for a root.
+func (g *Graph[K]) heaviestChains() (weights []float64, prev []int) {
+	weights = make([]float64, len(g.ids))
+	prev = make([]int, len(g.ids))
+	for _, v := range g.sorted {
+		prev[v] = -1
+		for _, before := range g.befores[v] {
+			if prev[v] < 0 || weights[before] > weights[prev[v]] {
+				prev[v] = before
+			}
+		}
+		weights[v] = g.weight(v)
+		if prev[v] >= 0 {
+			weights[v] += weights[prev[v]]
+		}
+	}
+	return
+}
+
+// chainTo follows prev from end back to a root, and returns the vertices met
+// from the root to end.
+func chainTo(prev []int, end int) []int {
+	path := []int{}
+	for v := end; v >= 0; v = prev[v] {
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// depths returns the length of the longest dependency chain leading to each
+// vertex, along with the number of distinct depths.
+//
+// They're memoized until the graph is mutated, and must not be modified.
+func (g *Graph[K]) depths() (depths []int, n int) {
+	g.memo.mu.Lock()
+	defer g.memo.mu.Unlock()
+
+	if g.memo.depths != nil {
+		return g.memo.depths, g.memo.layers
+	}
+	defer func() {
+		g.memo.depths, g.memo.layers = depths, n
+	}()
+
+	depths = make([]int, len(g.ids))
+
+	for _, v := range g.sorted {
+		d := depths[v]
+		if d >= n {
+			n = d + 1
+		}
+		for _, after := range g.afters[v] {
+			if depths[after] <= d {
+				depths[after] = d + 1
+			}
+		}
+	}
+
+	return
+}
+
+// heights returns the length of the longest dependency chain starting from
+// each vertex, memoized until the graph is mutated. It must not be modified.
+func (g *Graph[K]) heights() []int {
+	g.memo.mu.Lock()
+	defer g.memo.mu.Unlock()
+
+	if g.memo.heights != nil {
+		return g.memo.heights
+	}
+
+	heights := make([]int, len(g.ids))
+	for i := len(g.sorted) - 1; i >= 0; i-- {
+		v := g.sorted[i]
+		for _, after := range g.afters[v] {
+			if heights[after] >= heights[v] {
+				heights[v] = heights[after] + 1
+			}
+		}
+	}
+	g.memo.heights = heights
+
+	return heights
+}
+
+// Stats summarizes the shape of a graph.
+type Stats struct {
+	Vertices int // number of keys
+	Edges    int // number of edges
+	Roots    int // number of keys without dependencies
+	Leaves   int // number of keys without dependents
+	Depth    int // length of the longest dependency chain, in edges
+	MaxLayer int // number of keys in the largest layer
+}
+
+// Stats returns a summary of the shape of the graph.
+func (g *Graph[K]) Stats() Stats {
+	s := Stats{Vertices: len(g.sorted)}
+	for _, v := range g.sorted {
+		s.Edges += len(g.afters[v])
+		if len(g.befores[v]) == 0 {
+			s.Roots++
+		}
+		if len(g.afters[v]) == 0 {
+			s.Leaves++
+		}
+	}
+
+	depths, n := g.depths()
+	if n > 0 {
+		s.Depth = n - 1
+	}
+	sizes := make([]int, n)
+	for _, v := range g.sorted {
+		if sizes[depths[v]]++; sizes[depths[v]] > s.MaxLayer {
+			s.MaxLayer = sizes[depths[v]]
+		}
+	}
+
+	return s
+}
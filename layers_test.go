@@ -0,0 +1,162 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphLayers(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d;
+	a -> c -> d;
+	c -> e;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layers := g.Layers()
+	if len(layers) != 3 {
+		t.Fatalf("expected 3 layers, got %v", layers)
+	}
+	if !reflect.DeepEqual(layers[0], []string{"a"}) {
+		t.Fatalf("unexpected first layer %v", layers[0])
+	}
+	if !sameKeys(layers[1], []string{"b", "c"}) || !sameKeys(layers[2], []string{"d", "e"}) {
+		t.Fatalf("unexpected layers %v", layers)
+	}
+}
+
+// sameKeys reports whether a and b contain the same keys in any order.
+func sameKeys[K comparable](a, b []K) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	m := make(map[K]int)
+	for _, k := range a {
+		m[k]++
+	}
+	for _, k := range b {
+		m[k]--
+	}
+	for _, n := range m {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGraphCriticalPath(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> c -> d;
+	a -> d;
+	x -> c;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a", "b", "c", "d"}
+	if path := g.CriticalPath(); !reflect.DeepEqual(path, expected) {
+		t.Fatalf("expected critical path %v != %v", expected, path)
+	}
+
+	testCases := []struct {
+		id     string
+		depth  int
+		height int
+	}{
+		{"a", 0, 3},
+		{"x", 0, 2},
+		{"c", 2, 1},
+		{"d", 3, 0},
+		{"y", -1, -1},
+	}
+	for _, tt := range testCases {
+		if depth := g.Depth(tt.id); depth != tt.depth {
+			t.Fatalf("expected Depth(%s) %d != %d", tt.id, tt.depth, depth)
+		}
+		if height := g.Height(tt.id); height != tt.height {
+			t.Fatalf("expected Height(%s) %d != %d", tt.id, tt.height, height)
+		}
+	}
+	// memoized depths and heights follow mutations
+	if err := g.AddEdge("d", "e"); err != nil {
+		t.Fatal(err)
+	}
+	if depth, height := g.Depth("e"), g.Height("a"); depth != 4 || height != 4 {
+		t.Fatalf("expected depth 4 and height 4 after adding an edge, got %d and %d", depth, height)
+	}
+}
+
+func TestGraphWeightedCriticalPath(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> c -> d;
+	a -> d;
+	x -> c;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := g.CriticalPathWeight(); w != 4 {
+		t.Fatalf("expected critical path weight 4, got %v", w)
+	}
+
+	for id, w := range map[string]float64{"a": 1, "b": 1, "x": 5, "d": 0.5} {
+		if err := g.SetWeight(id, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.SetWeight("y", 1); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+
+	expected := []string{"x", "c", "d"}
+	if path := g.CriticalPath(); !reflect.DeepEqual(path, expected) {
+		t.Fatalf("expected critical path %v != %v", expected, path)
+	}
+	if w := g.CriticalPathWeight(); w != 6.5 {
+		t.Fatalf("expected critical path weight 6.5, got %v", w)
+	}
+	if w := g.Reverse().Weight("x"); w != 5 {
+		t.Fatalf("expected weights to be copied to derived graphs, got %v", w)
+	}
+}
+
+func TestGraphStats(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d;
+	a -> c -> d;
+	c -> e;
+	x;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := toposort.Stats{
+		Vertices: 6,
+		Edges:    5,
+		Roots:    2,
+		Leaves:   3,
+		Depth:    2,
+		MaxLayer: 2,
+	}
+	if stats := g.Stats(); stats != expected {
+		t.Fatalf("expected stats %+v != %+v", expected, stats)
+	}
+
+	empty, err := toposort.NewGraph(map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := empty.Stats(); stats != (toposort.Stats{}) {
+		t.Fatalf("expected empty stats, got %+v", stats)
+	}
+}
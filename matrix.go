@@ -0,0 +1,48 @@
+package toposort
+
+import (
+	"context"
+	"fmt"
+)
+
+// AdjacencyMatrix returns the graph as an adjacency matrix along with its
+// keys in topological order, where matrix[i][j] reports whether there's an
+// edge from ids[i] to ids[j], meaning that ids[j] depends on ids[i]. Since
+// the keys are sorted, the matrix is strictly upper triangular.
+func (g *Graph[K]) AdjacencyMatrix() (matrix [][]bool, ids []K) {
+	n := len(g.sorted)
+	cells := make([]bool, n*n)
+	matrix = make([][]bool, n)
+	for i, v := range g.sorted {
+		matrix[i] = cells[i*n : (i+1)*n : (i+1)*n]
+		for _, after := range g.afters[v] {
+			matrix[i][g.position[after]] = true
+		}
+	}
+
+	return matrix, g.keys(g.sorted)
+}
+
+// NewGraphFromMatrix builds a graph from an adjacency matrix, where
+// matrix[i][j] reports whether ids[j] depends on ids[i], and sorts it
+// topologically. The matrix must be square, with a row for each key. See
+// NewGraph for the other errors it returns.
+func NewGraphFromMatrix[K comparable](matrix [][]bool, ids []K, opts ...Option) (*Graph[K], error) {
+	if len(matrix) != len(ids) {
+		return nil, fmt.Errorf("adjacency matrix of %d rows for %d keys", len(matrix), len(ids))
+	}
+
+	edges := [][2]K{}
+	for i, row := range matrix {
+		if len(row) != len(ids) {
+			return nil, fmt.Errorf("row %d of adjacency matrix has %d columns for %d keys", i, len(row), len(ids))
+		}
+		for j, edge := range row {
+			if edge {
+				edges = append(edges, [2]K{ids[i], ids[j]})
+			}
+		}
+	}
+
+	return newGraph(context.Background(), ids, edges, newOptions(opts))
+}
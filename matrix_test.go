@@ -0,0 +1,87 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphAdjacencyMatrix(t *testing.T) {
+	g := newExampleGraph(t)
+
+	matrix, ids := g.AdjacencyMatrix()
+	if !reflect.DeepEqual(ids, g.SortedIDs()) {
+		t.Fatalf("expected keys %v != %v", g.SortedIDs(), ids)
+	}
+	expected := [][]bool{
+		{false, true, false, false},
+		{false, false, true, false},
+		{false, false, false, true},
+		{false, false, false, false},
+	}
+	if !reflect.DeepEqual(matrix, expected) {
+		t.Fatalf("expected matrix %v != %v", expected, matrix)
+	}
+
+	loaded, err := toposort.NewGraphFromMatrix(matrix, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Edges(), g.Edges()) {
+		t.Fatalf("expected edges %v != %v", g.Edges(), loaded.Edges())
+	}
+}
+
+func TestNewGraphFromMatrix(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		matrix [][]bool
+		ids    []int
+		sorted []int
+		err    error
+	}{
+		{
+			desc:   "sorted",
+			matrix: [][]bool{{false, false, false}, {true, false, false}, {false, true, false}},
+			ids:    []int{1, 2, 3},
+			sorted: []int{3, 2, 1},
+		},
+		{
+			desc:   "cyclic",
+			matrix: [][]bool{{false, true}, {true, false}},
+			ids:    []int{1, 2},
+			err:    toposort.ErrCircular,
+		},
+		{
+			desc:   "not square",
+			matrix: [][]bool{{false, true}, {false}},
+			ids:    []int{1, 2},
+		},
+		{
+			desc:   "missing row",
+			matrix: [][]bool{{false, true}},
+			ids:    []int{1, 2},
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			g, err := toposort.NewGraphFromMatrix(tt.matrix, tt.ids)
+			if tt.sorted == nil {
+				if err == nil || tt.err != nil && !errors.Is(err, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(g.SortedIDs(), tt.sorted) {
+				t.Fatalf("expected sorted value %v != %v", tt.sorted, g.SortedIDs())
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package toposort
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mermaid returns the graph as a Mermaid flowchart definition, which can be
+// embedded in Markdown documents rendered by GitHub or GitLab.
+//
+// Vertices are declared in topological order and edges point from each key
+// to the keys depending on it, followed by the edges left out to break
+// cycles, as listed by DroppedEdges, which are dotted and styled red.
+func (g *Graph[K]) Mermaid() string {
+	var b strings.Builder
+
+	b.WriteString("graph TD\n")
+	for i, v := range g.sorted {
+		fmt.Fprintf(&b, "\tn%d[%s]\n", i, mermaidLabel(g.ids[v]))
+	}
+
+	n := 0
+	for _, v := range g.sorted {
+		for _, after := range g.afters[v] {
+			fmt.Fprintf(&b, "\tn%d --> n%d\n", g.position[v], g.position[after])
+			n++
+		}
+	}
+	broken := g.brokenEdges()
+	for _, e := range broken {
+		fmt.Fprintf(&b, "\tn%d -.-> n%d\n", g.position[g.vertex[e.From]], g.position[g.vertex[e.To]])
+	}
+	for i := range broken {
+		fmt.Fprintf(&b, "\tlinkStyle %d stroke:red\n", n+i)
+	}
+
+	return b.String()
+}
+
+// mermaidLabel formats a key as a quoted Mermaid label.
+func mermaidLabel[K comparable](id K) string {
+	return `"` + strings.ReplaceAll(fmt.Sprint(id), `"`, "#quot;") + `"`
+}
@@ -0,0 +1,52 @@
+package toposort_test
+
+import (
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphMermaid(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		`Nick "the boss"`: "Sophie",
+		"Sophie":          "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `graph TD
+	n0["Jonas"]
+	n1["Sophie"]
+	n2["Nick #quot;the boss#quot;"]
+	n0 --> n1
+	n1 --> n2
+`
+	if s := g.Mermaid(); s != expected {
+		t.Fatalf("expected Mermaid output %q != %q", expected, s)
+	}
+}
+
+func TestGraphMermaidDroppedEdges(t *testing.T) {
+	g, err := toposort.NewGraphFromEdges([]toposort.Edge[string]{
+		{From: "a", To: "b"},
+		{From: "b", To: "c"},
+		{From: "c", To: "a"},
+	}, toposort.WithCyclePolicy(toposort.CycleIgnore))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `graph TD
+	n0["a"]
+	n1["b"]
+	n2["c"]
+	n0 --> n1
+	n1 --> n2
+	n2 -.-> n0
+	linkStyle 2 stroke:red
+`
+	if s := g.Mermaid(); s != expected {
+		t.Fatalf("expected Mermaid output %q != %q", expected, s)
+	}
+}
@@ -0,0 +1,32 @@
+package toposort
+
+import "time"
+
+// BuildMetrics reports the construction of a graph, for services exporting
+// metrics about the graphs they build.
+type BuildMetrics struct {
+	Keys       int           // keys of the graph
+	Edges      int           // edges of the graph
+	Duplicates int           // duplicate edges dropped
+	Cycles     int           // cycles found, besides those broken by Dropped
+	Dropped    int           // edges dropped by the cycle policy
+	Duration   time.Duration // time spent building, sorting and validating
+	Failed     bool          // whether construction failed
+}
+
+// buildMetrics returns the metrics of g, built from n edges in d, or of a
+// failed construction if g is nil.
+func buildMetrics[K comparable](g *Graph[K], n int, d time.Duration, failed bool) BuildMetrics {
+	m := BuildMetrics{Duration: d, Failed: failed || g == nil}
+	if g == nil {
+		return m
+	}
+	for _, afters := range g.afters {
+		m.Edges += len(afters)
+	}
+	m.Keys = len(g.ids)
+	m.Cycles = len(g.cycles)
+	m.Dropped = len(g.dropped)
+	m.Duplicates = n - m.Edges - m.Dropped
+	return m
+}
@@ -0,0 +1,71 @@
+package toposort_test
+
+import (
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestWithMetrics(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		edges    []toposort.Edge[string]
+		opts     []toposort.Option
+		expected toposort.BuildMetrics
+	}{
+		{
+			desc: "acyclic",
+			edges: []toposort.Edge[string]{
+				{From: "a", To: "b"},
+				{From: "b", To: "c"},
+				{From: "a", To: "b"},
+			},
+			expected: toposort.BuildMetrics{Keys: 3, Edges: 2, Duplicates: 1},
+		},
+		{
+			desc: "cyclic",
+			edges: []toposort.Edge[string]{
+				{From: "a", To: "b"},
+				{From: "b", To: "a"},
+			},
+			expected: toposort.BuildMetrics{Keys: 2, Edges: 2, Cycles: 1, Failed: true},
+		},
+		{
+			desc: "cycle broken",
+			edges: []toposort.Edge[string]{
+				{From: "a", To: "b"},
+				{From: "b", To: "a"},
+			},
+			opts:     []toposort.Option{toposort.WithCyclePolicy(toposort.CycleIgnore)},
+			expected: toposort.BuildMetrics{Keys: 2, Edges: 1, Dropped: 1},
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			var (
+				m     toposort.BuildMetrics
+				calls int
+			)
+			opts := append(append([]toposort.Option{}, tt.opts...), toposort.WithMetrics(func(bm toposort.BuildMetrics) {
+				m = bm
+				calls++
+			}))
+			_, err := toposort.NewGraphFromEdges(tt.edges, opts...)
+			if (err != nil) != tt.expected.Failed {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if calls != 1 {
+				t.Fatalf("expected 1 call, got %d", calls)
+			}
+			if m.Duration < 0 {
+				t.Fatalf("unexpected duration %v", m.Duration)
+			}
+			m.Duration = 0
+			if m != tt.expected {
+				t.Fatalf("expected %+v, got %+v", tt.expected, m)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package toposort
 import (
 	"errors"
 	"fmt"
+	"io"
 )
 
 // MultiError stores multiple errors.
@@ -10,6 +11,29 @@ import (
 // Borrowed from the AppEngine SDK.
 type MultiError []error
 
+// Errors returns the errors stored in m.
+func (m MultiError) Errors() []error {
+	return append([]error{}, m...)
+}
+
+// WrappedErrors returns the errors stored in m, leaving out nil ones, like
+// the errors printed with %+v.
+func (m MultiError) WrappedErrors() []error {
+	errs := []error{}
+	for _, e := range m {
+		if e != nil {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// Unwrap returns the errors stored in m, so that errors.Is and errors.As can
+// inspect each of them.
+func (m MultiError) Unwrap() []error {
+	return m
+}
+
 func (m MultiError) Is(target error) bool {
 	for _, e := range m {
 		if errors.Is(e, target) {
@@ -19,6 +43,15 @@ func (m MultiError) Is(target error) bool {
 	return false
 }
 
+func (m MultiError) As(target any) bool {
+	for _, e := range m {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m MultiError) Error() string {
 	s, n := "", 0
 	for _, e := range m {
@@ -39,3 +72,29 @@ func (m MultiError) Error() string {
 	}
 	return fmt.Sprintf("%s (and %d other errors)", s, n-1)
 }
+
+// Format implements fmt.Formatter. The %+v verb prints every error on its own
+// line, while the other verbs print the summary returned by Error.
+func (m MultiError) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		n := 0
+		for _, e := range m {
+			if e == nil {
+				continue
+			}
+			if n > 0 {
+				io.WriteString(f, "\n")
+			}
+			fmt.Fprintf(f, "%+v", e)
+			n++
+		}
+		if n == 0 {
+			io.WriteString(f, m.Error())
+		}
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", m.Error())
+	default:
+		io.WriteString(f, m.Error())
+	}
+}
@@ -0,0 +1,60 @@
+package toposort_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestMultiErrorUnwrap(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+
+	m := toposort.MultiError{errA, &toposort.CycleError[string]{Path: []string{"x", "y", "x"}}, errB}
+	err := fmt.Errorf("wrapped: %w", m)
+
+	if !errors.Is(err, errB) {
+		t.Fatalf("expected %v to match %v", err, errB)
+	}
+	var ce *toposort.CycleError[string]
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected %v to contain a cycle error", err)
+	}
+
+	errs := m.Errors()
+	if len(errs) != 3 || errs[0] != errA || errs[2] != errB {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	errs[0] = nil
+	if m[0] != errA {
+		t.Fatal("expected Errors to return a copy")
+	}
+}
+
+func TestMultiErrorFormat(t *testing.T) {
+	m := toposort.MultiError{errors.New("a"), nil, errors.New("b"), errors.New("c")}
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{"%v", "a (and 2 other errors)"},
+		{"%s", "a (and 2 other errors)"},
+		{"%q", `"a (and 2 other errors)"`},
+		{"%+v", "a\nb\nc"},
+	}
+	for _, tt := range testCases {
+		if s := fmt.Sprintf(tt.format, m); s != tt.expected {
+			t.Fatalf("expected %s output %q != %q", tt.format, tt.expected, s)
+		}
+	}
+
+	if s := fmt.Sprintf("%+v", toposort.MultiError{}); s != "(0 errors)" {
+		t.Fatalf("unexpected output %q", s)
+	}
+
+	if errs := m.WrappedErrors(); len(errs) != 3 || errs[0] != m[0] || errs[1] != m[2] || errs[2] != m[3] {
+		t.Fatalf("unexpected wrapped errors %v", errs)
+	}
+}
@@ -0,0 +1,315 @@
+package toposort
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AddEdge adds an edge from from to to, meaning that to depends on from,
+// adding the keys that are not in the graph yet. Keys are normalized like
+// the keys given to NewGraph.
+//
+// The topological order is patched in place with the Pearce-Kelly algorithm,
+// which only reorders the keys between the two ends of the edge, instead of
+// sorting the whole graph again. An edge closing a cycle is rejected with a
+// CycleError, or a SelfReferenceError, leaving the graph unchanged. Adding
+// an edge already in the graph does nothing, unless WithStrictEdges is given.
+//
+// Unlike NewGraph, AddEdge doesn't check the graph for multiple roots, nor
+// keep the order lexicographically smallest. It must not be called
+// concurrently with other methods of g.
+func (g *Graph[K]) AddEdge(from, to K) error {
+	originalFrom, originalTo := from, to
+	from, to = g.canonical(from), g.canonical(to)
+	if err := g.validateNewNames(from, to); err != nil {
+		return err
+	}
+	if from == to {
+		return &SelfReferenceError[K]{ID: originalFrom}
+	}
+
+	x, y := g.addVertex(from), g.addVertex(to)
+	g.remember(originalFrom, from)
+	g.remember(originalTo, to)
+	if contains(g.afters[x], y) {
+		if g.opts.strictEdges {
+			return fmt.Errorf("%w: %v -> %v", ErrDuplicateEdge, originalFrom, originalTo)
+		}
+		return nil
+	}
+
+	if lo, hi := g.position[y], g.position[x]; lo < hi {
+		forward, path := g.discover(y, g.afters, func(w int) bool { return g.position[w] <= hi }, x)
+		if path != nil {
+			return &CycleError[K]{Path: g.spellings(append([]int{x}, path...))}
+		}
+		backward, _ := g.discover(x, g.befores, func(w int) bool { return g.position[w] > lo }, -1)
+		g.reorder(backward, forward)
+	}
+
+	g.afters[x] = append(g.afters[x], y)
+	g.befores[y] = append(g.befores[y], x)
+	g.invalidate()
+
+	return nil
+}
+
+// AddVertex adds id to the graph as a key without edges, at the end of the
+// topological order, so that it's listed even if nothing relates it to the
+// other keys. The key is normalized like the keys given to NewGraph, and
+// adding a key already in the graph does nothing.
+//
+// Like AddEdge, it doesn't check the graph for multiple roots, and must not
+// be called concurrently with other methods of g.
+func (g *Graph[K]) AddVertex(id K) error {
+	original := id
+	id = g.canonical(id)
+	if err := g.validateNewNames(id); err != nil {
+		return err
+	}
+	if _, ok := g.vertex[id]; !ok {
+		g.addVertex(id)
+		g.invalidate()
+	}
+	g.remember(original, id)
+
+	return nil
+}
+
+// validateNewNames checks the keys that are not in the graph yet with the
+// name validator of the graph.
+func (g *Graph[K]) validateNewNames(ids ...K) error {
+	fresh := []K{}
+	for _, id := range ids {
+		if _, ok := g.vertex[id]; !ok {
+			fresh = append(fresh, id)
+		}
+	}
+	if err := validateNames(fresh, g.opts.validators, g.spelling); err != nil {
+		return err
+	}
+	return nil
+}
+
+// addVertex returns the vertex of id, adding it at the end of the
+// topological order if it's not in the graph yet.
+func (g *Graph[K]) addVertex(id K) int {
+	n := len(g.ids)
+	v := g.intern(id)
+	if v == n {
+		g.position = append(g.position, len(g.sorted))
+		g.sorted = append(g.sorted, v)
+		g.recursive = append(g.recursive, false)
+		if g.attrs != nil {
+			g.attrs = append(g.attrs, nil)
+		}
+		if g.weights != nil {
+			g.weights = append(g.weights, 1)
+		}
+	}
+	return v
+}
+
+// discover collects the vertices reachable from v following the given edges
+// through the vertices satisfying within. If target is reached, the path from
+// v to target is returned as well.
+func (g *Graph[K]) discover(v int, edges [][]int, within func(w int) bool, target int) (vs []int, path []int) {
+	parent := map[int]int{v: -1}
+	stack := []int{v}
+	for len(stack) > 0 {
+		w := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		vs = append(vs, w)
+		for _, u := range edges[w] {
+			if _, ok := parent[u]; ok || !within(u) {
+				continue
+			}
+			parent[u] = w
+			if u == target {
+				for ; u >= 0; u = parent[u] {
+					path = append([]int{u}, path...)
+				}
+				return
+			}
+			stack = append(stack, u)
+		}
+	}
+	return
+}
+
+// reorder moves the vertices of backward before the vertices of forward,
+// reusing the positions they occupy in the topological order.
+func (g *Graph[K]) reorder(backward, forward []int) {
+	byPosition := func(vs []int) {
+		sort.Slice(vs, func(i, j int) bool { return g.position[vs[i]] < g.position[vs[j]] })
+	}
+	byPosition(backward)
+	byPosition(forward)
+
+	vs := append(backward, forward...)
+	positions := make([]int, len(vs))
+	for i, v := range vs {
+		positions[i] = g.position[v]
+	}
+	sort.Ints(positions)
+
+	for i, v := range vs {
+		g.sorted[positions[i]] = v
+		g.position[v] = positions[i]
+	}
+}
+
+// invalidate drops the state derived from the edges of the graph after a
+// mutation.
+func (g *Graph[K]) invalidate() {
+	g.memo = &memo[K]{reach: make(map[int][]bool)}
+}
+
+// RemoveEdge removes the edge from from to to, so that to no longer depends
+// on from. It returns an error wrapping ErrNotFound if there's no such edge.
+//
+// Removing an edge never invalidates the topological order, which is kept as
+// it is.
+func (g *Graph[K]) RemoveEdge(from, to K) error {
+	x, ok := g.lookup(from)
+	y, ok2 := g.lookup(to)
+	if !ok || !ok2 || !contains(g.afters[x], y) {
+		return fmt.Errorf("%w: edge %v -> %v", ErrNotFound, from, to)
+	}
+
+	g.afters[x] = without(g.afters[x], y)
+	g.befores[y] = without(g.befores[y], x)
+	delete(g.labels, [2]K{g.ids[x], g.ids[y]})
+	g.invalidate()
+
+	return nil
+}
+
+// RemoveVertex removes id from the graph along with its edges, keeping the
+// relative order of the remaining keys. It returns an error wrapping
+// ErrNotFound if id is not in the graph.
+func (g *Graph[K]) RemoveVertex(id K) error {
+	v, ok := g.lookup(id)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNotFound, id)
+	}
+	id = g.ids[v]
+
+	for _, u := range g.afters[v] {
+		g.befores[u] = without(g.befores[u], v)
+		delete(g.labels, [2]K{id, g.ids[u]})
+	}
+	for _, u := range g.befores[v] {
+		g.afters[u] = without(g.afters[u], v)
+		delete(g.labels, [2]K{g.ids[u], id})
+	}
+
+	copy(g.sorted[g.position[v]:], g.sorted[g.position[v]+1:])
+	g.sorted = g.sorted[:len(g.sorted)-1]
+	for i := g.position[v]; i < len(g.sorted); i++ {
+		g.position[g.sorted[i]] = i
+	}
+
+	// move the last vertex into the slot of v, so that vertices stay
+	// contiguous
+	last := len(g.ids) - 1
+	if v != last {
+		for _, u := range g.afters[last] {
+			replace(g.befores[u], last, v)
+		}
+		for _, u := range g.befores[last] {
+			replace(g.afters[u], last, v)
+		}
+		g.ids[v] = g.ids[last]
+		g.vertex[g.ids[v]] = v
+		g.afters[v], g.befores[v] = g.afters[last], g.befores[last]
+		g.position[v] = g.position[last]
+		g.sorted[g.position[v]] = v
+		g.recursive[v] = g.recursive[last]
+		if g.attrs != nil {
+			g.attrs[v] = g.attrs[last]
+		}
+		if g.weights != nil {
+			g.weights[v] = g.weights[last]
+		}
+	}
+
+	delete(g.vertex, id)
+	delete(g.originals, id)
+	g.ids = g.ids[:last]
+	g.afters, g.befores = g.afters[:last], g.befores[:last]
+	g.position = g.position[:last]
+	g.recursive = g.recursive[:last]
+	if g.attrs != nil {
+		g.attrs = g.attrs[:last]
+	}
+	if g.weights != nil {
+		g.weights = g.weights[:last]
+	}
+	g.invalidate()
+
+	return nil
+}
+
+// contains reports whether v is in vs.
+func contains(vs []int, v int) bool {
+	for _, w := range vs {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+// without removes every occurrence of v from vs in place.
+func without(vs []int, v int) []int {
+	kept := vs[:0]
+	for _, w := range vs {
+		if w != v {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
+
+// replace substitutes w for every occurrence of v in vs.
+func replace(vs []int, v, w int) {
+	for i := range vs {
+		if vs[i] == v {
+			vs[i] = w
+		}
+	}
+}
+
+// Clone returns an independent copy of g, so that a snapshot can be handed
+// to other goroutines while g keeps being mutated. Attribute values are
+// copied as they are.
+func (g *Graph[K]) Clone() *Graph[K] {
+	ng := &Graph[K]{
+		ids:       append([]K{}, g.ids...),
+		vertex:    make(map[K]int, len(g.vertex)),
+		afters:    make([][]int, len(g.afters)),
+		befores:   make([][]int, len(g.befores)),
+		sorted:    append([]int{}, g.sorted...),
+		position:  append([]int{}, g.position...),
+		recursive: append([]bool{}, g.recursive...),
+		cycles:    make([][]int, len(g.cycles)),
+		opts:      g.opts,
+		warnings:  append(MultiError(nil), g.warnings...),
+		dropped:   append([]Edge[K](nil), g.dropped...),
+	}
+	for id, v := range g.vertex {
+		ng.vertex[id] = v
+	}
+	for v := range g.afters {
+		ng.afters[v] = append([]int(nil), g.afters[v]...)
+		ng.befores[v] = append([]int(nil), g.befores[v]...)
+	}
+	for i, path := range g.cycles {
+		ng.cycles[i] = append([]int{}, path...)
+	}
+	ng.invalidate()
+	g.copyAttrs(ng)
+
+	return ng
+}
@@ -0,0 +1,216 @@
+package toposort_test
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/onur1/toposort"
+)
+
+// checkOrder fails if the sorted keys of g don't respect its edges.
+func checkOrder[K comparable](t *testing.T, g *toposort.Graph[K]) {
+	t.Helper()
+
+	for _, e := range g.Edges() {
+		if !g.Before(e[0], e[1]) {
+			t.Fatalf("expected %v to come before %v in %v", e[0], e[1], g.SortedIDs())
+		}
+	}
+}
+
+func TestGraphAddEdge(t *testing.T) {
+	g := newExampleGraph(t)
+
+	if err := g.AddEdge("Barbara", "Ruby"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdge("Daniel", "Sophie"); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, g)
+	if !g.Reachable("Daniel", "Ruby") {
+		t.Fatal("expected Ruby to be reachable from Daniel")
+	}
+
+	sorted := g.SortedIDs()
+	err := g.AddEdge("Barbara", "Sophie")
+	var ce *toposort.CycleError[string]
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected a cycle error, got %v", err)
+	}
+	if expected := []string{"Barbara", "Sophie", "Nick", "Barbara"}; !reflect.DeepEqual(ce.Path, expected) {
+		t.Fatalf("expected cycle path %v != %v", expected, ce.Path)
+	}
+	if err := g.AddEdge("Nick", "Nick"); !errors.Is(err, toposort.ErrSelfReference) {
+		t.Fatalf("expected error %v != %v", toposort.ErrSelfReference, err)
+	}
+	if !reflect.DeepEqual(g.SortedIDs(), sorted) {
+		t.Fatalf("expected order %v to be left unchanged, got %v", sorted, g.SortedIDs())
+	}
+}
+
+func TestGraphAddEdgeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	g, err := toposort.NewGraph(map[int]int{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cycles := 0
+	for i := 0; i < 2000; i++ {
+		from, to := r.Intn(200), r.Intn(200)
+		err := g.AddEdge(from, to)
+		if errors.Is(err, toposort.ErrCircular) {
+			cycles++
+			if !g.Reachable(to, from) {
+				t.Fatalf("unexpected cycle error %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cycles == 0 {
+		t.Fatal("expected some edges to be rejected")
+	}
+	checkOrder(t, g)
+
+	for _, scc := range g.SCCs() {
+		if len(scc) > 1 {
+			t.Fatalf("expected the graph to be acyclic, found %v", scc)
+		}
+	}
+}
+
+func TestGraphSortedIDsMemo(t *testing.T) {
+	g := newExampleGraph(t)
+
+	sorted := g.SortedIDs()
+	if again := g.SortedIDs(); &again[0] != &sorted[0] {
+		t.Fatal("expected the sorted keys to be memoized")
+	}
+	if err := g.AddEdge("Barbara", "Ruby"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Jonas", "Sophie", "Nick", "Barbara", "Ruby"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected order %v != %v", expected, g.SortedIDs())
+	}
+	if expected := []string{"Jonas", "Sophie", "Nick", "Barbara"}; !reflect.DeepEqual(sorted, expected) {
+		t.Fatalf("expected previously returned keys %v to be left unchanged, got %v", expected, sorted)
+	}
+}
+
+func TestGraphRemoveEdge(t *testing.T) {
+	g := newExampleGraph(t)
+
+	if err := g.RemoveEdge("Sophie", "Nick"); err != nil {
+		t.Fatal(err)
+	}
+	if g.Reachable("Jonas", "Barbara") {
+		t.Fatal("expected Barbara to be unreachable from Jonas")
+	}
+	if err := g.RemoveEdge("Sophie", "Nick"); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+	if err := g.AddEdge("Barbara", "Jonas"); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, g)
+}
+
+func TestGraphRemoveVertex(t *testing.T) {
+	g := newExampleGraph(t)
+	if err := g.SetAttr("Barbara", "age", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.RemoveVertex("Sophie"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RemoveVertex("Sophie"); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+
+	if expected := []string{"Jonas", "Nick", "Barbara"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected order %v != %v", expected, g.SortedIDs())
+	}
+	if expected := [][2]string{{"Nick", "Barbara"}}; !reflect.DeepEqual(g.Edges(), expected) {
+		t.Fatalf("expected edges %v != %v", expected, g.Edges())
+	}
+	if age, ok := g.Attr("Barbara", "age"); !ok || age != 7 {
+		t.Fatalf("expected Barbara to keep its attributes, got %v", age)
+	}
+	if g.Index("Sophie") != -1 {
+		t.Fatal("expected Sophie to be removed")
+	}
+
+	if err := g.AddEdge("Barbara", "Jonas"); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, g)
+}
+
+func TestGraphClone(t *testing.T) {
+	g := newExampleGraph(t)
+	if err := g.SetAttr("Nick", "age", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	c := g.Clone()
+	if err := c.AddEdge("Barbara", "Ruby"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemoveVertex("Sophie"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetAttr("Nick", "age", 8); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"Jonas", "Sophie", "Nick", "Barbara"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected order %v != %v", expected, g.SortedIDs())
+	}
+	if expected := []string{"Jonas", "Nick", "Barbara", "Ruby"}; !reflect.DeepEqual(c.SortedIDs(), expected) {
+		t.Fatalf("expected order %v != %v", expected, c.SortedIDs())
+	}
+	if age, _ := g.Attr("Nick", "age"); age != 7 {
+		t.Fatalf("expected the attributes of the original to be unchanged, got %v", age)
+	}
+	if !g.Reachable("Jonas", "Barbara") {
+		t.Fatal("expected Barbara to be reachable from Jonas in the original")
+	}
+}
+
+func TestGraphAddVertex(t *testing.T) {
+	g := newExampleGraph(t)
+
+	if err := g.AddVertex("Ruby"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddVertex("Nick"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Jonas", "Sophie", "Nick", "Barbara", "Ruby"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected order %v != %v", expected, g.SortedIDs())
+	}
+	if expected := []string{"Jonas", "Ruby"}; !reflect.DeepEqual(g.Roots(), expected) {
+		t.Fatalf("expected roots %v != %v", expected, g.Roots())
+	}
+	if err := g.AddEdge("Ruby", "Jonas"); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, g)
+
+	g, err := toposort.NewGraph(map[string]string{"b": "a"}, toposort.WithAllowedRunes(unicode.IsLower))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddVertex("C"); !errors.Is(err, toposort.ErrInvalidName) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidName, err)
+	}
+}
@@ -0,0 +1,147 @@
+package toposort
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CanonicalID returns the key of the graph id stands for once normalized,
+// like "nick" for "NICK" with WithCaseFolding, and whether it's in the
+// graph. Without normalization, keys stand for themselves.
+//
+// Other methods normalize the keys they are given the same way, but return
+// the keys of the graph, which OriginalID maps back to a spelling given.
+func (g *Graph[K]) CanonicalID(id K) (K, bool) {
+	if normalize, ok := g.opts.normalize.(func(K) K); ok {
+		id = normalize(id)
+	}
+	_, ok := g.vertex[id]
+	return id, ok
+}
+
+// OriginalID returns the spelling id was given in, like "NICK" for "nick"
+// with WithCaseFolding, and whether it's in the graph. Of several spellings
+// of the same key, the lexicographically smallest one is kept, whatever the
+// order they're given in. Keys left as they are by normalization are their
+// own spelling.
+//
+// Like the other methods, it accepts any spelling of a key.
+func (g *Graph[K]) OriginalID(id K) (K, bool) {
+	id, ok := g.CanonicalID(id)
+	if original, found := g.originals[id]; found && ok {
+		return original, true
+	}
+	return id, ok
+}
+
+// canonical returns id normalized like the keys of g.
+func (g *Graph[K]) canonical(id K) K {
+	id, _ = g.CanonicalID(id)
+	return id
+}
+
+// lookup returns the vertex of id, normalized like the keys of g, and
+// whether there's one.
+func (g *Graph[K]) lookup(id K) (int, bool) {
+	v, ok := g.vertex[g.canonical(id)]
+	return v, ok
+}
+
+// spelling returns the spelling id, a key of g, was given in, so that errors
+// name keys as given.
+func (g *Graph[K]) spelling(id K) K {
+	if original, ok := g.originals[id]; ok {
+		return original
+	}
+	return id
+}
+
+// spellings translates vertices to the spellings their keys were given in.
+func (g *Graph[K]) spellings(vs []int) []K {
+	keys := make([]K, len(vs))
+	for i, v := range vs {
+		keys[i] = g.spelling(g.ids[v])
+	}
+	return keys
+}
+
+// remember records original as a spelling of the key c, unless it's c
+// itself or a smaller spelling is known.
+func (g *Graph[K]) remember(original, c K) {
+	if original == c {
+		return
+	}
+	if known, ok := g.originals[c]; ok && !lexicalLess(original, known) {
+		return
+	}
+	if g.originals == nil {
+		g.originals = make(map[K]K)
+	}
+	g.originals[c] = original
+}
+
+// normalizeKeys maps the given keys and edges through normalize, which is
+// expected to be a func(K) K, returning the smallest spelling of the keys
+// changed by normalize as well. If strict, distinct keys mapped to the same
+// key are reported as errors wrapping ErrAmbiguousID.
+func normalizeKeys[K comparable](ids []K, edges [][2]K, normalize any, strict bool) ([]K, [][2]K, map[K]K, error) {
+	fn, ok := normalize.(func(K) K)
+	if !ok {
+		return nil, nil, nil, MultiError{fmt.Errorf("%w: normalizer %T does not accept %T keys", ErrInvalidName, normalize, *new(K))}
+	}
+
+	smallest := make(map[K]K)
+	originals := make(map[K][]K)
+	canonical := func(id K) K {
+		c := fn(id)
+		if known, ok := smallest[c]; !ok || lexicalLess(id, known) {
+			smallest[c] = id
+		}
+		if strict {
+			found := false
+			for _, o := range originals[c] {
+				if o == id {
+					found = true
+					break
+				}
+			}
+			if !found {
+				originals[c] = append(originals[c], id)
+			}
+		}
+		return c
+	}
+
+	nids := make([]K, len(ids))
+	for i, id := range ids {
+		nids[i] = canonical(id)
+	}
+	nedges := make([][2]K, len(edges))
+	for i, e := range edges {
+		nedges[i] = [2]K{canonical(e[0]), canonical(e[1])}
+	}
+
+	var err MultiError
+	for c, keys := range originals {
+		if len(keys) > 1 {
+			sort.Slice(keys, func(i, j int) bool {
+				return lexicalLess(keys[i], keys[j])
+			})
+			err = append(err, fmt.Errorf("%w: %v are all %v", ErrAmbiguousID, keys, c))
+		}
+	}
+	if err != nil {
+		sort.Slice(err, func(i, j int) bool {
+			return err[i].Error() < err[j].Error()
+		})
+		return nil, nil, nil, err
+	}
+
+	for c, original := range smallest {
+		if original == c {
+			delete(smallest, c)
+		}
+	}
+
+	return nids, nedges, smallest, nil
+}
@@ -0,0 +1,199 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestWithCaseFolding(t *testing.T) {
+	relations := map[string]string{
+		"Barbara": "Nick",
+		"NICK":    "Sophie",
+		"sophie":  "Jonas",
+	}
+
+	g, err := toposort.NewGraph(relations, toposort.WithCaseFolding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"jonas", "sophie", "nick", "barbara"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, g.SortedIDs())
+	}
+
+	for _, id := range []string{"NICK", "Nick", "nick"} {
+		if canonical, ok := g.CanonicalID(id); canonical != "nick" || !ok {
+			t.Fatalf("expected %s to stand for nick, got %s (%t)", id, canonical, ok)
+		}
+	}
+	if _, ok := g.CanonicalID("Ruby"); ok {
+		t.Fatal("expected Ruby not to be in the graph")
+	}
+
+	if err := g.AddEdge("BARBARA", "Ruby"); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Before("barbara", "ruby") {
+		t.Fatalf("expected added keys to be folded, got %v", g.SortedIDs())
+	}
+
+	_, err = toposort.NewGraph(relations, toposort.WithCaseFolding(), toposort.WithStrictIDs())
+	if !errors.Is(err, toposort.ErrAmbiguousID) {
+		t.Fatalf("expected error %v != %v", toposort.ErrAmbiguousID, err)
+	}
+	expected := []string{
+		"ambiguous id: [NICK Nick] are all nick",
+		"ambiguous id: [Sophie sophie] are all sophie",
+	}
+	messages := []string{}
+	for _, e := range err.(toposort.MultiError).Errors() {
+		messages = append(messages, e.Error())
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Fatalf("expected errors %q != %q", expected, messages)
+	}
+
+	if _, err := toposort.NewGraph(map[int]int{1: 2}, toposort.WithCaseFolding()); !errors.Is(err, toposort.ErrInvalidName) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidName, err)
+	}
+}
+
+func TestWithTrimSpace(t *testing.T) {
+	g, err := toposort.NewGraphFromCSV(strings.NewReader("Jonas ,\nSophie, Jonas\nNick , Sophie \n"), 0, 1, toposort.WithTrimSpace())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Jonas", "Sophie", "Nick"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %q != %q", expected, g.SortedIDs())
+	}
+}
+
+func TestWithNormalizer(t *testing.T) {
+	stripVersion := func(id string) string {
+		if i := strings.IndexByte(id, '@'); i >= 0 {
+			return id[:i]
+		}
+		return id
+	}
+
+	g, err := toposort.NewGraph(map[string]string{
+		"app":    " LIB@v1",
+		"lib@v2": "base",
+	}, toposort.WithTrimSpace(), toposort.WithCaseFolding(), toposort.WithNormalizer(stripVersion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"base", "lib", "app"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %q != %q", expected, g.SortedIDs())
+	}
+	if id, ok := g.CanonicalID("Lib@v3 "); id != "lib" || !ok {
+		t.Fatalf("expected lib, got %q (%t)", id, ok)
+	}
+}
+
+func TestGraphOriginalID(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"nick":    "Sophie",
+	}, toposort.WithCaseFolding())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.AddEdge("NICK", "X"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"NICK", "Nick", "x", "X"} {
+		if !g.Has(id) {
+			t.Fatalf("expected %s to be in the graph", id)
+		}
+	}
+	if parents := g.Parents("X"); !reflect.DeepEqual(parents, []string{"nick"}) {
+		t.Fatalf("expected parents [nick] != %v", parents)
+	}
+	if err := g.SetEdgeLabel("Nick", "X", "mentor"); err != nil {
+		t.Fatal(err)
+	}
+	if label, ok := g.EdgeLabel("nick", "x"); label != "mentor" || !ok {
+		t.Fatalf("expected label mentor, got %q (%t)", label, ok)
+	}
+
+	for id, expected := range map[string]string{
+		"nick":    "NICK",
+		"BARBARA": "Barbara",
+		"x":       "X",
+		"sophie":  "Sophie",
+	} {
+		if original, ok := g.OriginalID(id); original != expected || !ok {
+			t.Fatalf("expected %s to be spelled %s, got %s (%t)", id, expected, original, ok)
+		}
+	}
+
+	if err := g.RemoveEdge("NICK", "X"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RemoveVertex("X"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.OriginalID("X"); ok {
+		t.Fatal("expected X not to be in the graph")
+	}
+	if original, _ := g.Clone().OriginalID("nick"); original != "NICK" {
+		t.Fatalf("expected the clone to spell nick NICK, got %s", original)
+	}
+}
+
+func TestErrorsUseOriginalIDs(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		relations map[string]string
+		expected  []string // any of
+	}{
+		{
+			desc:      "cycle",
+			relations: map[string]string{"Barbara": "Nick", "Nick": "Barbara"},
+			expected:  []string{"cyclic: [Nick Barbara Nick]", "cyclic: [Barbara Nick Barbara]"},
+		},
+		{
+			desc:      "self reference",
+			relations: map[string]string{"Nick": "Nick"},
+			expected:  []string{"cyclic: self reference: Nick"},
+		},
+		{
+			desc:      "multiple roots",
+			relations: map[string]string{"Barbara": "Nick", "Ruby": "Daniel"},
+			expected:  []string{"multiple roots: [Daniel Nick]"},
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := toposort.NewGraph(tt.relations, toposort.WithCaseFolding(), toposort.WithLexicographicOrder())
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			errs := err.(toposort.MultiError).Errors()
+			if len(errs) != 1 {
+				t.Fatalf("expected a single error, got %v", errs)
+			}
+			for _, expected := range tt.expected {
+				if errs[0].Error() == expected {
+					return
+				}
+			}
+			t.Fatalf("expected any of %q, got %q", tt.expected, errs[0].Error())
+		})
+	}
+
+	g, err := toposort.NewGraph(map[string]string{"Barbara": "Nick"}, toposort.WithCaseFolding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdge("BARBARA", "Nick"); err == nil || err.Error() != "cyclic: [BARBARA Nick BARBARA]" {
+		t.Fatalf("expected the cycle to be spelled as given, got %v", err)
+	}
+}
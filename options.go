@@ -0,0 +1,383 @@
+package toposort
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Option configures how a graph is built and validated.
+type Option func(*options)
+
+type options struct {
+	allowMultipleRoots bool                  // accept forests
+	validators         []any                 // func(K) error checking every key
+	lexicographic      bool                  // emit the smallest order
+	priority           any                   // func(K) int ranking keys
+	groups             any                   // map[K]string of keys to keep together
+	csvHeader          bool                  // skip the first CSV record
+	trimSpace          bool                  // trim spaces around string keys
+	foldCase           func(string) string   // case mapping of string keys
+	normalizers        []func(string) string // custom normalization of string keys
+	normalize          any                   // func(K) K mapping keys to their canonical form
+	warnings           []error               // errors downgraded to warnings
+	skipValidation     bool                  // only check for cycles
+	ignoredLabels      []string              // labels of edges left out
+	optionalLabels     []string              // labels of edges left out if they close a cycle
+	cyclePolicy        CyclePolicy           // how cycles are handled
+	cycleCallback      any                   // func([]K) int picking the edges to break
+	strictEdges        bool                  // reject duplicate edges
+	parallelism        int                   // goroutines building large graphs, GOMAXPROCS if 0
+	logger             debugLogger           // diagnostics of construction, if any
+	metrics            func(BuildMetrics)    // report of each construction, if any
+	strictIDs          bool                  // reject distinct keys with the same canonical form
+	canonicalKeys      bool                  // keys given to build are normalized already
+	strictChain        bool                  // reject anything but a single chain
+	connected          bool                  // reject several components
+}
+
+// debugLogger is the part of a *slog.Logger used to log diagnostics, so
+// that the package still builds with Go versions before log/slog.
+type debugLogger interface {
+	Debug(msg string, args ...any)
+}
+
+// debug logs a diagnostic message with the logger of o, if any.
+func (o options) debug(msg string, args ...any) {
+	if o.logger != nil {
+		o.logger.Debug(msg, args...)
+	}
+}
+
+// relaxed returns o without the checks of the shape of a graph, for graphs
+// derived from another one, which may have any number of roots or
+// components, whatever the options it was built with. The keys of such
+// graphs are in canonical form already, so they aren't normalized again.
+func (o options) relaxed() options {
+	o.allowMultipleRoots = true
+	o.strictChain, o.connected = false, false
+	o.canonicalKeys = true
+	return o
+}
+
+func newOptions(opts []Option) (o options) {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.normalize = o.normalizer()
+	return
+}
+
+// normalizer composes the normalization steps of string keys, or returns nil
+// if there are none. Spaces are trimmed first, and custom normalizers run
+// last.
+func (o *options) normalizer() any {
+	steps := []func(string) string{}
+	if o.trimSpace {
+		steps = append(steps, strings.TrimSpace)
+	}
+	if o.foldCase != nil {
+		steps = append(steps, o.foldCase)
+	}
+	steps = append(steps, o.normalizers...)
+	if len(steps) == 0 {
+		return nil
+	}
+
+	return func(s string) string {
+		for _, step := range steps {
+			s = step(s)
+		}
+		return s
+	}
+}
+
+// WithAllowMultipleRoots disables the multiple roots validation, so that a
+// forest of independent trees is sorted as a whole instead of being rejected
+// with ErrMultipleRoots.
+func WithAllowMultipleRoots() Option {
+	return func(o *options) {
+		o.allowMultipleRoots = true
+	}
+}
+
+// WithNameValidator makes NewGraph check every key with validate before
+// building the graph. Keys rejected by validate are reported as errors
+// wrapping ErrInvalidName. By default every key is accepted. Several
+// validators can be given, which are run in turn.
+//
+// The key type of validate must match the key type of the graph.
+func WithNameValidator[K comparable](validate func(K) error) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, validate)
+	}
+}
+
+// WithAllowedRunes makes NewGraph reject string keys with runes that aren't
+// allowed, with errors wrapping ErrInvalidName. Alphanumeric and Identifier
+// are common choices of allowed runes.
+func WithAllowedRunes(allowed func(r rune) bool) Option {
+	return WithNameValidator(func(id string) error {
+		for _, r := range id {
+			if !allowed(r) {
+				return fmt.Errorf("rune %q is not allowed", r)
+			}
+		}
+		return nil
+	})
+}
+
+// WithMinIDLength makes NewGraph reject string keys shorter than n runes,
+// with errors wrapping ErrInvalidName. Keys of any length, even empty, are
+// accepted by default, so WithMinIDLength(1) only rejects empty keys.
+func WithMinIDLength(n int) Option {
+	return WithNameValidator(func(id string) error {
+		if utf8.RuneCountInString(id) < n {
+			return fmt.Errorf("shorter than %d runes", n)
+		}
+		return nil
+	})
+}
+
+// Alphanumeric reports whether r is a letter or a digit, like in "node42".
+func Alphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Identifier reports whether r is a letter, a digit or one of the separators
+// commonly found in identifiers and paths, like in "svc-api", "pkg/util",
+// "a.b" or "x_y".
+func Identifier(r rune) bool {
+	return Alphanumeric(r) || strings.ContainsRune("-_./", r)
+}
+
+// WithLexicographicOrder makes the graph sorted in the lexicographically
+// smallest valid order, instead of an arbitrary one, so that the same input
+// always produces the same output.
+//
+// Keys of string, integer and floating-point kinds are compared by value,
+// and other keys by their fmt.Sprint formatting.
+func WithLexicographicOrder() Option {
+	return func(o *options) {
+		o.lexicographic = true
+	}
+}
+
+// WithPriority makes the graph sorted so that keys of higher priority come
+// as early as their dependencies allow. The dependencies of a key are
+// considered as urgent as the key itself, so that they aren't delayed by
+// keys of lower priority. Keys of the same priority are ordered as they would
+// be otherwise, lexicographically with WithLexicographicOrder.
+//
+// The key type of priority must match the key type of the graph.
+func WithPriority[K comparable](priority func(K) int) Option {
+	return func(o *options) {
+		o.priority = priority
+	}
+}
+
+// WithGroups makes the graph sorted so that keys of the same group, named
+// by groups, stay contiguous unless their dependencies force keys of other
+// groups in between, like migrations of the same schema depending on another
+// one. Keys without a group are ordered freely.
+//
+// The key type of groups must match the key type of the graph.
+func WithGroups[K comparable](groups map[K]string) Option {
+	return func(o *options) {
+		o.groups = groups
+	}
+}
+
+// WithCaseFolding makes string keys that only differ in case, like "NICK"
+// and "Nick", the same key, lower cased. Graph.CanonicalID maps keys as given
+// to the keys of the graph, and Graph.OriginalID maps them back to a spelling
+// they were given in. Methods of the graph accept any spelling of a key, and
+// errors name keys by that spelling.
+func WithCaseFolding() Option {
+	return func(o *options) {
+		o.foldCase = strings.ToLower
+	}
+}
+
+// WithIgnoredLabels makes NewGraphFromEdges leave out the edges with any of
+// the given labels, like soft dependencies, so that they neither constrain
+// the order nor close cycles. Other constructors ignore it.
+func WithIgnoredLabels(labels ...string) Option {
+	return func(o *options) {
+		o.ignoredLabels = append(o.ignoredLabels, labels...)
+	}
+}
+
+// WithOptionalLabels makes NewGraphFromEdges treat the edges with any of
+// the given labels as optional, like the soft dependencies of init systems
+// and package managers. Optional edges are honored in the order they're
+// given, unless they would close a cycle, in which case they're left out and
+// reported by DroppedEdges. Other constructors ignore it.
+func WithOptionalLabels(labels ...string) Option {
+	return func(o *options) {
+		o.optionalLabels = append(o.optionalLabels, labels...)
+	}
+}
+
+// WithCyclePolicy makes NewGraph handle cyclic input according to policy,
+// breaking cycles by dropping edges instead of failing. The dropped edges are
+// reported by DroppedEdges.
+func WithCyclePolicy(policy CyclePolicy) Option {
+	return func(o *options) {
+		o.cyclePolicy = policy
+	}
+}
+
+// WithCycleCallback makes NewGraph break cycles with the edges picked by
+// callback, which is given the path of each cycle found, as in CycleError,
+// and returns the index i of the edge from path[i] to path[i+1] to drop, or
+// -1 to reject the cycle, which is then reported as usual while the edges
+// picked for other cycles are still dropped. It implies the CycleCallback
+// policy.
+//
+// The key type of callback must match the key type of the graph.
+func WithCycleCallback[K comparable](callback func(path []K) int) Option {
+	return func(o *options) {
+		o.cyclePolicy = CycleCallback
+		o.cycleCallback = callback
+	}
+}
+
+// WithParallelism sets the number of goroutines building graphs of many
+// edges, which is GOMAXPROCS by default. Graphs of fewer edges, and all
+// graphs with a parallelism of 1, are built by the calling goroutine alone.
+// The graph built is the same either way.
+func WithParallelism(n int) Option {
+	return func(o *options) {
+		o.parallelism = n
+	}
+}
+
+// WithMetrics makes NewGraph and the other constructors call report with the
+// metrics of each construction, failed or not, so that they can be exported
+// without wrapping every call. It's called from the goroutine building the
+// graph.
+func WithMetrics(report func(m BuildMetrics)) Option {
+	return func(o *options) {
+		o.metrics = report
+	}
+}
+
+// WithStrictEdges makes NewGraph reject edges given more than once, with
+// errors wrapping ErrDuplicateEdge, instead of dropping the duplicates
+// silently.
+func WithStrictEdges() Option {
+	return func(o *options) {
+		o.strictEdges = true
+	}
+}
+
+// WithTrimSpace makes string keys trimmed of leading and trailing white
+// space, like the keys of CSV files padded for readability, so that "Nick "
+// and "Nick" are the same key.
+func WithTrimSpace() Option {
+	return func(o *options) {
+		o.trimSpace = true
+	}
+}
+
+// WithNormalizer makes string keys mapped through normalize, after the
+// built-in normalizations like WithTrimSpace or WithCaseFolding, so that all
+// the keys normalize maps to the same string are the same key. Several
+// normalizers can be given, which are run in turn.
+//
+// Unicode normalization, so that visually identical keys made of different
+// code points are the same key, and the case mappings of specific languages,
+// like the dotless i of Turkish, are provided by package
+// github.com/onur1/toposort/textnorm, which keeps golang.org/x/text out of
+// the dependencies of this package.
+func WithNormalizer(normalize func(string) string) Option {
+	return func(o *options) {
+		o.normalizers = append(o.normalizers, normalize)
+	}
+}
+
+// WithStrictIDs makes NewGraph reject distinct keys that are made the same
+// key by normalization, like WithCaseFolding, with errors wrapping
+// ErrAmbiguousID, instead of merging them silently.
+func WithStrictIDs() Option {
+	return func(o *options) {
+		o.strictIDs = true
+	}
+}
+
+// WithWarnings downgrades the validation errors matching any of targets with
+// errors.Is to warnings, so that a graph is built despite them, like
+// WithWarnings(ErrMultipleRoots) for a forest. Warnings are listed by
+// Graph.Warnings. Cycles can't be downgraded, as a graph with cycles can't be
+// sorted.
+func WithWarnings(targets ...error) Option {
+	return func(o *options) {
+		o.warnings = append(o.warnings, targets...)
+	}
+}
+
+// WithoutValidation skips the validation of keys and of the shape of the
+// graph, like the multiple roots validation, for input known to be a clean
+// DAG, making NewGraph cheaper on large graphs. Cycles are still reported,
+// as they're found while sorting anyway.
+func WithoutValidation() Option {
+	return func(o *options) {
+		o.skipValidation = true
+	}
+}
+
+// split separates the errors downgraded to warnings from the others.
+func (o *options) split(err MultiError) (errs, warnings MultiError) {
+	for _, e := range err {
+		if o.warns(e) {
+			warnings = append(warnings, e)
+		} else {
+			errs = append(errs, e)
+		}
+	}
+	return
+}
+
+// warns reports whether err is downgraded to a warning.
+func (o *options) warns(err error) bool {
+	if errors.Is(err, ErrCircular) {
+		return false
+	}
+	for _, target := range o.warnings {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCSVHeader makes NewGraphFromCSV skip the first record, which holds the
+// column names. Other constructors ignore it.
+func WithCSVHeader() Option {
+	return func(o *options) {
+		o.csvHeader = true
+	}
+}
+
+// WithStrictChain makes NewGraph reject graphs that are not a single chain
+// of keys, each depending on the previous one, with an error wrapping
+// ErrNotChain. See Graph.Chain.
+func WithStrictChain() Option {
+	return func(o *options) {
+		o.strictChain = true
+	}
+}
+
+// WithConnected makes NewGraph reject graphs made of several weakly
+// connected components with a DisconnectedError. Unlike the multiple roots
+// validation, it accepts keys with several roots as long as they're linked
+// through common dependents, so it's typically combined with
+// WithAllowMultipleRoots to tell apart unrelated data from a forest.
+func WithConnected() Option {
+	return func(o *options) {
+		o.connected = true
+	}
+}
@@ -0,0 +1,246 @@
+package toposort_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestWithNameValidator(t *testing.T) {
+	minLength := func(id string) error {
+		if len(id) < 2 {
+			return fmt.Errorf("too short")
+		}
+		return nil
+	}
+
+	if _, err := toposort.NewGraph(map[string]string{
+		"job-1": "pkg_a",
+	}, toposort.WithNameValidator(minLength)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := toposort.NewGraph(map[string]string{
+		"B": "A",
+		"C": "A",
+	}, toposort.WithNameValidator(minLength))
+	if !errors.Is(err, toposort.ErrInvalidName) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidName, err)
+	}
+	if n := len(err.(toposort.MultiError)); n != 3 {
+		t.Fatalf("expected 3 errors, got %d: %v", n, err)
+	}
+
+	_, err = toposort.NewGraph(map[int]int{
+		1: 0,
+	}, toposort.WithNameValidator(minLength))
+	if !errors.Is(err, toposort.ErrInvalidName) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidName, err)
+	}
+}
+
+func TestWithAllowedRunes(t *testing.T) {
+	relations := map[string]string{
+		"node42":   "svc-api",
+		"pkg/util": "svc-api",
+	}
+
+	if _, err := toposort.NewGraph(relations, toposort.WithAllowMultipleRoots(), toposort.WithAllowedRunes(toposort.Identifier)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := toposort.NewGraph(relations, toposort.WithAllowMultipleRoots(), toposort.WithAllowedRunes(toposort.Alphanumeric))
+	if !errors.Is(err, toposort.ErrInvalidName) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidName, err)
+	}
+	if n := len(err.(toposort.MultiError)); n != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", n, err)
+	}
+
+	_, err = toposort.NewGraph(map[string]string{"a b": "c"},
+		toposort.WithAllowedRunes(toposort.Identifier),
+		toposort.WithNameValidator(func(id string) error { return fmt.Errorf("rejected") }),
+	)
+	if expected := `invalid name a b: rune ' ' is not allowed`; !strings.Contains(fmt.Sprintf("%+v", err), expected) {
+		t.Fatalf("expected error %q, got %+v", expected, err)
+	}
+}
+
+func TestWithMinIDLength(t *testing.T) {
+	relations := map[string]string{"B": "A", "": "A"}
+
+	if _, err := toposort.NewGraph(relations, toposort.WithAllowMultipleRoots()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := toposort.NewGraph(relations, toposort.WithAllowMultipleRoots(), toposort.WithMinIDLength(1))
+	if !errors.Is(err, toposort.ErrInvalidName) {
+		t.Fatalf("expected error %v != %v", toposort.ErrInvalidName, err)
+	}
+	if n := len(err.(toposort.MultiError)); n != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", n, err)
+	}
+
+	_, err = toposort.NewGraph(map[string]string{"Bé": "Aé", "C": "Aé"}, toposort.WithAllowMultipleRoots(), toposort.WithMinIDLength(2))
+	if expected := "invalid name C: shorter than 2 runes"; err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q != %v", expected, err)
+	}
+}
+
+func TestWithLexicographicOrder(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	e -> b -> a;
+	c -> a;
+	d -> c;
+	d -> f;
+}`), toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"d", "c", "e", "b", "a", "f"}
+	if sorted := g.SortedIDs(); !reflect.DeepEqual(sorted, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, sorted)
+	}
+
+	sorted, err := toposort.Sort(map[int]int{
+		10: 1,
+		9:  1,
+		2:  1,
+	}, toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int{1, 2, 9, 10}; !reflect.DeepEqual(sorted, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, sorted)
+	}
+}
+
+func TestWithPriority(t *testing.T) {
+	dot := `digraph {
+	a -> b;
+	c -> d -> urgent;
+	e;
+}`
+	priority := func(id string) int {
+		if id == "urgent" {
+			return 10
+		}
+		if id == "e" {
+			return 1
+		}
+		return 0
+	}
+
+	g, err := toposort.ParseDOT(strings.NewReader(dot), toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder(), toposort.WithPriority(priority))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"c", "d", "urgent", "e", "a", "b"}
+	if sorted := g.SortedIDs(); !reflect.DeepEqual(sorted, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, sorted)
+	}
+
+	_, err = toposort.ParseDOT(strings.NewReader(dot), toposort.WithAllowMultipleRoots(), toposort.WithPriority(func(int) int { return 0 }))
+	if err == nil {
+		t.Fatal("expected a priority of the wrong key type to be rejected")
+	}
+}
+
+func TestWithGroups(t *testing.T) {
+	groups := map[string]string{
+		"x1": "a",
+		"z1": "a",
+		"y1": "b",
+		"y2": "b",
+	}
+
+	testCases := []struct {
+		desc     string
+		dot      string
+		expected []string
+	}{
+		{
+			desc:     "contiguous",
+			dot:      `digraph { x1 -> z1; y1 -> y2; w }`,
+			expected: []string{"w", "x1", "z1", "y1", "y2"},
+		},
+		{
+			desc:     "forced interleaving",
+			dot:      `digraph { x1 -> z1; y1 -> y2; y1 -> z1 }`,
+			expected: []string{"x1", "y1", "y2", "z1"},
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			g, err := toposort.ParseDOT(strings.NewReader(tt.dot), toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder(), toposort.WithGroups(groups))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sorted := g.SortedIDs(); !reflect.DeepEqual(sorted, tt.expected) {
+				t.Fatalf("expected sorted value %+v != %+v", tt.expected, sorted)
+			}
+		})
+	}
+}
+
+func TestWithWarnings(t *testing.T) {
+	relations := map[string]string{
+		"b": "a",
+		"d": "c",
+	}
+
+	if _, err := toposort.NewGraph(relations); !errors.Is(err, toposort.ErrMultipleRoots) {
+		t.Fatalf("expected error %v != %v", toposort.ErrMultipleRoots, err)
+	}
+
+	g, err := toposort.NewGraph(relations, toposort.WithWarnings(toposort.ErrMultipleRoots, toposort.ErrCircular))
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings := g.Warnings()
+	if len(warnings) != 1 || !errors.Is(warnings[0], toposort.ErrMultipleRoots) {
+		t.Fatalf("expected a multiple roots warning, got %v", warnings)
+	}
+	if len(g.SortedIDs()) != 4 {
+		t.Fatalf("expected 4 keys, got %v", g.SortedIDs())
+	}
+
+	_, err = toposort.NewGraph(map[string]string{"a": "b", "b": "a"}, toposort.WithWarnings(toposort.ErrCircular))
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+
+	g, err = toposort.NewGraph(map[string]string{"b": "a"}, toposort.WithMinIDLength(2), toposort.WithWarnings(toposort.ErrInvalidName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(g.Warnings()); n != 2 {
+		t.Fatalf("expected 2 warnings, got %v", g.Warnings())
+	}
+}
+
+func TestWithoutValidation(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"b": "a",
+		"d": "c",
+	}, toposort.WithMinIDLength(2), toposort.WithoutValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.SortedIDs()) != 4 {
+		t.Fatalf("expected 4 keys, got %v", g.SortedIDs())
+	}
+
+	_, err = toposort.NewGraph(map[string]string{"a": "b", "b": "a"}, toposort.WithoutValidation())
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+}
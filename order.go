@@ -0,0 +1,298 @@
+package toposort
+
+import (
+	"container/heap"
+	"fmt"
+	"reflect"
+)
+
+// kahn sorts the given acyclic graph with Kahn's algorithm, always emitting
+// the smallest ready vertex according to less. Vertices are initially
+// considered in vs order.
+//
+// If group isn't nil, it numbers the group of each vertex, or is -1 for
+// vertices without a group, and the ready vertices of the group of the last
+// emitted vertex are emitted first, so that groups stay contiguous as long
+// as the edges allow it.
+func kahn(afters, befores [][]int, vs []int, less func(a, b int) bool, group []int) []int {
+	indegree := make([]int, len(afters))
+	ready := &vertexHeap{less: less}
+	byGroup := make(map[int]*vertexHeap)
+	push := func(v int) {
+		heap.Push(ready, v)
+		if group != nil && group[v] >= 0 {
+			h, ok := byGroup[group[v]]
+			if !ok {
+				h = &vertexHeap{less: less}
+				byGroup[group[v]] = h
+			}
+			heap.Push(h, v)
+		}
+	}
+	for _, v := range vs {
+		if indegree[v] = len(befores[v]); indegree[v] == 0 {
+			push(v)
+		}
+	}
+
+	// vertices are pushed on both heaps, and skipped on the other one once
+	// emitted
+	emitted := make([]bool, len(afters))
+	pop := func(h *vertexHeap) int {
+		for h != nil && h.Len() > 0 {
+			if v := heap.Pop(h).(int); !emitted[v] {
+				return v
+			}
+		}
+		return -1
+	}
+
+	sorted := make([]int, 0, len(vs))
+	current := -1
+	for {
+		v := -1
+		if current >= 0 {
+			v = pop(byGroup[current])
+		}
+		if v < 0 {
+			if v = pop(ready); v < 0 {
+				break
+			}
+		}
+		emitted[v] = true
+		sorted = append(sorted, v)
+		if group != nil {
+			current = group[v]
+		}
+		for _, after := range afters[v] {
+			indegree[after]--
+			if indegree[after] == 0 {
+				push(after)
+			}
+		}
+	}
+
+	return sorted
+}
+
+// less returns the order in which ready vertices are emitted according to the
+// options of g, or nil if any order will do.
+func (g *Graph[K]) less() (func(a, b int) bool, error) {
+	var tiebreak func(a, b int) bool
+	if g.opts.lexicographic {
+		tiebreak = func(a, b int) bool { return lexicalLess(g.ids[a], g.ids[b]) }
+	}
+	if g.opts.priority == nil {
+		return tiebreak, nil
+	}
+	if tiebreak == nil {
+		tiebreak = rankLess(g.sorted)
+	}
+
+	priority, ok := g.opts.priority.(func(K) int)
+	if !ok {
+		return nil, fmt.Errorf("priority %T does not accept %T keys", g.opts.priority, *new(K))
+	}
+
+	// a key is as urgent as the most urgent key depending on it
+	own := make([]int, len(g.ids))
+	urgency := make([]int, len(g.ids))
+	for i := len(g.sorted) - 1; i >= 0; i-- {
+		v := g.sorted[i]
+		own[v] = priority(g.ids[v])
+		urgency[v] = own[v]
+		for _, after := range g.afters[v] {
+			if urgency[after] > urgency[v] {
+				urgency[v] = urgency[after]
+			}
+		}
+	}
+
+	return func(a, b int) bool {
+		if urgency[a] != urgency[b] {
+			return urgency[a] > urgency[b]
+		}
+		if own[a] != own[b] {
+			return own[a] > own[b]
+		}
+		return tiebreak(a, b)
+	}, nil
+}
+
+// rankLess orders vertices as they are in sorted.
+func rankLess(sorted []int) func(a, b int) bool {
+	rank := make([]int, len(sorted))
+	for i, v := range sorted {
+		rank[v] = i
+	}
+	return func(a, b int) bool { return rank[a] < rank[b] }
+}
+
+// groups numbers the group of each vertex according to the options of g, -1
+// standing for no group, or returns nil if there are no groups.
+func (g *Graph[K]) groups() ([]int, error) {
+	if g.opts.groups == nil {
+		return nil, nil
+	}
+	groups, ok := g.opts.groups.(map[K]string)
+	if !ok {
+		return nil, fmt.Errorf("groups %T do not accept %T keys", g.opts.groups, *new(K))
+	}
+
+	numbers := make(map[string]int)
+	group := make([]int, len(g.ids))
+	for v, id := range g.ids {
+		name, ok := groups[id]
+		if !ok {
+			group[v] = -1
+			continue
+		}
+		n, ok := numbers[name]
+		if !ok {
+			n = len(numbers)
+			numbers[name] = n
+		}
+		group[v] = n
+	}
+
+	return group, nil
+}
+
+// AllOrders enumerates the valid topological orders of the graph, stopping
+// after limit orders unless limit is less than 1. The number of orders grows
+// exponentially with the number of independent keys, so this is only suited
+// to small graphs.
+func (g *Graph[K]) AllOrders(limit int) [][]K {
+	indegree := make([]int, len(g.ids))
+	for v := range g.ids {
+		indegree[v] = len(g.befores[v])
+	}
+
+	used := make([]bool, len(g.ids))
+	order := make([]int, 0, len(g.ids))
+	orders := [][]K{}
+
+	var visit func() bool
+
+	visit = func() bool {
+		if len(order) == len(g.sorted) {
+			orders = append(orders, g.keys(order))
+			return limit > 0 && len(orders) >= limit
+		}
+		for _, v := range g.sorted {
+			if used[v] || indegree[v] > 0 {
+				continue
+			}
+			used[v] = true
+			order = append(order, v)
+			for _, after := range g.afters[v] {
+				indegree[after]--
+			}
+			if visit() {
+				return true
+			}
+			for _, after := range g.afters[v] {
+				indegree[after]++
+			}
+			order = order[:len(order)-1]
+			used[v] = false
+		}
+		return false
+	}
+
+	visit()
+
+	return orders
+}
+
+// IsValidOrder reports whether order lists every key of the graph exactly
+// once, each after the keys it depends on, like an order computed by another
+// scheduler.
+func (g *Graph[K]) IsValidOrder(order []K) bool {
+	if len(order) != len(g.ids) {
+		return false
+	}
+
+	position := make([]int, len(g.ids))
+	for i := range position {
+		position[i] = -1
+	}
+	for i, id := range order {
+		v, ok := g.lookup(id)
+		if !ok || position[v] >= 0 {
+			return false
+		}
+		position[v] = i
+	}
+	for v, afters := range g.afters {
+		for _, after := range afters {
+			if position[after] < position[v] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// IsValidTopologicalOrder reports whether order lists every key of deps
+// exactly once, each after the keys it depends on, where deps maps each key
+// to the keys it depends on, as read by ReadDependencies. Keys only listed
+// as dependencies must be in order as well.
+func IsValidTopologicalOrder[K comparable](order []K, deps map[K][]K) bool {
+	position := make(map[K]int, len(order))
+	for i, id := range order {
+		if _, ok := position[id]; ok {
+			return false
+		}
+		position[id] = i
+	}
+
+	for id, ds := range deps {
+		i, ok := position[id]
+		if !ok {
+			return false
+		}
+		for _, d := range ds {
+			if j, ok := position[d]; !ok || j >= i {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// vertexHeap is a min-heap of vertices ordered by less.
+type vertexHeap struct {
+	vs   []int
+	less func(a, b int) bool
+}
+
+func (h *vertexHeap) Len() int           { return len(h.vs) }
+func (h *vertexHeap) Less(i, j int) bool { return h.less(h.vs[i], h.vs[j]) }
+func (h *vertexHeap) Swap(i, j int)      { h.vs[i], h.vs[j] = h.vs[j], h.vs[i] }
+func (h *vertexHeap) Push(x any)         { h.vs = append(h.vs, x.(int)) }
+
+func (h *vertexHeap) Pop() any {
+	v := h.vs[len(h.vs)-1]
+	h.vs = h.vs[:len(h.vs)-1]
+	return v
+}
+
+// lexicalLess compares keys of string, integer and floating-point kinds by
+// value, and other keys by their fmt.Sprint formatting.
+func lexicalLess[K comparable](a, b K) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.String:
+		return va.String() < vb.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return va.Int() < vb.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return va.Uint() < vb.Uint()
+	case reflect.Float32, reflect.Float64:
+		return va.Float() < vb.Float()
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
@@ -0,0 +1,72 @@
+package toposort_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphAllOrders(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d;
+	a -> c -> d;
+}`), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"a", "b", "c", "d"},
+		{"a", "c", "b", "d"},
+	}
+	if orders := g.AllOrders(0); !reflect.DeepEqual(orders, expected) {
+		t.Fatalf("expected orders %v != %v", expected, orders)
+	}
+	if orders := g.AllOrders(1); !reflect.DeepEqual(orders, expected[:1]) {
+		t.Fatalf("expected orders %v != %v", expected[:1], orders)
+	}
+}
+
+func TestGraphIsValidOrder(t *testing.T) {
+	g := newExampleGraph(t)
+
+	testCases := []struct {
+		desc     string
+		order    []string
+		expected bool
+	}{
+		{desc: "sorted", order: []string{"Jonas", "Sophie", "Nick", "Barbara"}, expected: true},
+		{desc: "reversed", order: []string{"Barbara", "Nick", "Sophie", "Jonas"}},
+		{desc: "missing key", order: []string{"Jonas", "Sophie", "Nick"}},
+		{desc: "duplicate key", order: []string{"Jonas", "Sophie", "Nick", "Nick"}},
+		{desc: "unknown key", order: []string{"Jonas", "Sophie", "Nick", "Ruby"}},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			if valid := g.IsValidOrder(tt.order); valid != tt.expected {
+				t.Fatalf("expected %t != %t", tt.expected, valid)
+			}
+		})
+	}
+}
+
+func TestIsValidTopologicalOrder(t *testing.T) {
+	deps := map[string][]string{"test": {"build"}, "build": {"fetch"}}
+
+	if !toposort.IsValidTopologicalOrder([]string{"fetch", "build", "lint", "test"}, deps) {
+		t.Fatal("expected the order to be valid")
+	}
+	if toposort.IsValidTopologicalOrder([]string{"build", "fetch", "test"}, deps) {
+		t.Fatal("expected build before fetch to be invalid")
+	}
+	if toposort.IsValidTopologicalOrder([]string{"build", "test"}, deps) {
+		t.Fatal("expected a missing dependency to be invalid")
+	}
+	if toposort.IsValidTopologicalOrder([]string{"fetch", "build", "test", "test"}, deps) {
+		t.Fatal("expected a duplicate key to be invalid")
+	}
+}
@@ -0,0 +1,122 @@
+package toposort
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// parallelThreshold is the number of edges from which graphs are built by
+// several goroutines, below which the overhead isn't worth it.
+const parallelThreshold = 1 << 16
+
+// workers returns the number of goroutines building a graph of n edges.
+func (o options) workers(n int) int {
+	if n < parallelThreshold {
+		return 1
+	}
+	if o.parallelism > 0 {
+		return o.parallelism
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// buildParallel interns the given edges into g with several goroutines,
+// numbering the keys and ordering the edges exactly as build would.
+//
+// Each goroutine collects the distinct keys of a chunk of edges, which are
+// then interned chunk by chunk, so that the keys repeated across the input
+// are looked up in the shared map only once per chunk. Edges are then
+// bucketed by the shards of their vertices, and linked by goroutines owning a
+// shard each.
+func (g *Graph[K]) buildParallel(edges [][2]K, workers int) MultiError {
+	size := (len(edges) + workers - 1) / workers
+	chunks := make([][][2]K, 0, workers)
+	for i := 0; i < len(edges); i += size {
+		end := i + size
+		if end > len(edges) {
+			end = len(edges)
+		}
+		chunks = append(chunks, edges[i:end])
+	}
+
+	parallel := func(n int, f func(i int)) {
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			go func(i int) {
+				defer wg.Done()
+				f(i)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	// distinct keys of each chunk, in order of appearance
+	firsts := make([][]K, len(chunks))
+	parallel(len(chunks), func(i int) {
+		seen := make(map[K]struct{})
+		for _, e := range chunks[i] {
+			for _, id := range e {
+				if _, ok := seen[id]; !ok {
+					seen[id] = struct{}{}
+					firsts[i] = append(firsts[i], id)
+				}
+			}
+		}
+	})
+	for _, ids := range firsts {
+		for _, id := range ids {
+			g.intern(id)
+		}
+	}
+
+	pairs := make([][2]int, len(edges))
+	parallel(len(chunks), func(i int) {
+		offset := i * size
+		for j, e := range chunks[i] {
+			pairs[offset+j] = [2]int{g.vertex[e[0]], g.vertex[e[1]]}
+		}
+	})
+
+	// indices of the pairs from and to the vertices of each shard, in order
+	froms, tos := make([][]int, workers), make([][]int, workers)
+	for i, p := range pairs {
+		froms[p[0]%workers] = append(froms[p[0]%workers], i)
+		tos[p[1]%workers] = append(tos[p[1]%workers], i)
+	}
+
+	// each goroutine links the edges from the vertices of its shard, then
+	// the edges to them, so that no slice is shared
+	duplicate := make([]bool, len(pairs))
+	parallel(workers, func(w int) {
+		seen := make(map[[2]int]bool, len(froms[w]))
+		for _, i := range froms[w] {
+			p := pairs[i]
+			if seen[p] {
+				duplicate[i] = true
+				continue
+			}
+			seen[p] = true
+			g.afters[p[0]] = append(g.afters[p[0]], p[1])
+		}
+	})
+	parallel(workers, func(w int) {
+		for _, i := range tos[w] {
+			if p := pairs[i]; !duplicate[i] {
+				g.befores[p[1]] = append(g.befores[p[1]], p[0])
+			}
+		}
+	})
+
+	var errs MultiError
+	if g.opts.strictEdges {
+		for i, dup := range duplicate {
+			if dup {
+				errs = append(errs, fmt.Errorf("%w: %v -> %v", ErrDuplicateEdge, g.spelling(edges[i][0]), g.spelling(edges[i][1])))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,65 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+	"github.com/onur1/toposort/toposortgen"
+)
+
+func TestParallelBuild(t *testing.T) {
+	edges := toposortgen.DAG(3000, 0.02, 1)
+	if len(edges) < 1<<16 {
+		t.Fatalf("expected enough edges to build in parallel, got %d", len(edges))
+	}
+	edges = append(edges, edges[:10]...)
+
+	sequential, err := toposort.NewGraphFromEdges(edges, toposort.WithAllowMultipleRoots(), toposort.WithParallelism(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := toposort.NewGraphFromEdges(edges, toposort.WithAllowMultipleRoots(), toposort.WithParallelism(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parallel.SortedIDs(), sequential.SortedIDs()) {
+		t.Fatal("expected the same order")
+	}
+	if !reflect.DeepEqual(parallel.Edges(), sequential.Edges()) {
+		t.Fatal("expected the same edges")
+	}
+
+	_, err = toposort.NewGraphFromEdges(edges, toposort.WithParallelism(4), toposort.WithStrictEdges())
+	var m toposort.MultiError
+	if !errors.As(err, &m) || len(m) != 10 || !errors.Is(err, toposort.ErrDuplicateEdge) {
+		t.Fatalf("expected 10 duplicate edges, got %v", err)
+	}
+}
+
+func BenchmarkNewGraphFromEdges(b *testing.B) {
+	edges := toposortgen.DAG(200000, 0.0001, 1) // about two million edges
+
+	benchmarks := []struct {
+		desc        string
+		parallelism int
+	}{
+		{"sequential", 1},
+		{"parallel", 0},
+	}
+	for _, bb := range benchmarks {
+		bb := bb
+
+		b.Run(bb.desc, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				_, err := toposort.NewGraphFromEdges(edges, toposort.WithAllowMultipleRoots(), toposort.WithParallelism(bb.parallelism))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
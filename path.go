@@ -0,0 +1,93 @@
+package toposort
+
+// Path returns one of the shortest chains of dependencies leading from from
+// to to, both included, explaining why to depends on from. It returns false
+// if to doesn't depend on from, or either of them is not in the graph.
+func (g *Graph[K]) Path(from, to K) ([]K, bool) {
+	v, ok := g.lookup(from)
+	if !ok {
+		return nil, false
+	}
+	w, ok := g.lookup(to)
+	if !ok {
+		return nil, false
+	}
+
+	parent := map[int]int{v: -1}
+	queue := []int{v}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		if u == w {
+			path := []int{}
+			for ; u >= 0; u = parent[u] {
+				path = append(path, u)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return g.keys(path), true
+		}
+		for _, after := range g.afters[u] {
+			if _, ok := parent[after]; ok || g.position[after] > g.position[w] {
+				continue
+			}
+			parent[after] = u
+			queue = append(queue, after)
+		}
+	}
+
+	return nil, false
+}
+
+// AllPaths enumerates the chains of dependencies leading from from to to,
+// stopping after limit paths unless limit is less than 1. The number of
+// paths may grow exponentially with the size of the graph.
+func (g *Graph[K]) AllPaths(from, to K, limit int) [][]K {
+	paths := [][]K{}
+
+	v, ok := g.lookup(from)
+	if !ok {
+		return paths
+	}
+	w, ok := g.lookup(to)
+	if !ok {
+		return paths
+	}
+
+	// only follow the vertices leading to w
+	leads := make([]bool, len(g.ids))
+	leads[w] = true
+	for _, u := range g.walk(w, g.befores) {
+		leads[u] = true
+	}
+	if !leads[v] {
+		return paths
+	}
+
+	path := []int{v}
+
+	var visit func(u int) bool
+
+	visit = func(u int) bool {
+		if u == w {
+			paths = append(paths, g.keys(path))
+			return limit > 0 && len(paths) >= limit
+		}
+		for _, after := range g.afters[u] {
+			if !leads[after] {
+				continue
+			}
+			path = append(path, after)
+			if visit(after) {
+				return true
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+
+	visit(v)
+
+	return paths
+}
@@ -0,0 +1,54 @@
+package toposort_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGraphPath(t *testing.T) {
+	g := newRunGraph(t)
+
+	testCases := []struct {
+		from, to string
+		expected []string
+		ok       bool
+	}{
+		{"a", "f", []string{"a", "b", "d", "f"}, true},
+		{"c", "f", []string{"c", "e", "f"}, true},
+		{"b", "b", []string{"b"}, true},
+		{"b", "e", nil, false},
+		{"f", "a", nil, false},
+		{"a", "z", nil, false},
+	}
+
+	for _, tc := range testCases {
+		path, ok := g.Path(tc.from, tc.to)
+		if ok != tc.ok || !reflect.DeepEqual(path, tc.expected) {
+			t.Fatalf("expected path from %s to %s %v (%t) != %v (%t)", tc.from, tc.to, tc.expected, tc.ok, path, ok)
+		}
+	}
+}
+
+func TestGraphAllPaths(t *testing.T) {
+	g := newRunGraph(t)
+
+	paths := g.AllPaths("a", "f", 0)
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 paths, got %v", paths)
+	}
+	joined := []string{}
+	for _, path := range paths {
+		joined = append(joined, strings.Join(path, "-"))
+	}
+	if expected := []string{"a-b-d-f", "a-c-e-f"}; !sameKeys(joined, expected) {
+		t.Fatalf("expected paths %v != %v", expected, joined)
+	}
+
+	if paths := g.AllPaths("a", "f", 1); len(paths) != 1 {
+		t.Fatalf("expected 1 path, got %v", paths)
+	}
+	if paths := g.AllPaths("b", "e", 0); len(paths) != 0 {
+		t.Fatalf("expected no paths, got %v", paths)
+	}
+}
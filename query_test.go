@@ -0,0 +1,338 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func newExampleGraph(t *testing.T) *toposort.Graph[string] {
+	t.Helper()
+
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+func TestGraphVerticesEdges(t *testing.T) {
+	g := newExampleGraph(t)
+
+	vertices := []string{"Jonas", "Sophie", "Nick", "Barbara"}
+	if v := g.Vertices(); !reflect.DeepEqual(v, vertices) {
+		t.Fatalf("expected vertices %v != %v", vertices, v)
+	}
+	edges := [][2]string{{"Jonas", "Sophie"}, {"Sophie", "Nick"}, {"Nick", "Barbara"}}
+	if e := g.Edges(); !reflect.DeepEqual(e, edges) {
+		t.Fatalf("expected edges %v != %v", edges, e)
+	}
+	if n := g.Len(); n != len(vertices) {
+		t.Fatalf("expected length %d != %d", len(vertices), n)
+	}
+	if !g.Has("Nick") || g.Has("Ruby") {
+		t.Fatal("expected Nick but not Ruby to be in the graph")
+	}
+}
+
+func TestGraphRootsLeaves(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d;
+	a -> c;
+	e -> c;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if roots := g.Roots(); !sameKeys(roots, []string{"a", "e"}) {
+		t.Fatalf("unexpected roots %v", roots)
+	}
+	if leaves := g.Leaves(); !sameKeys(leaves, []string{"c", "d"}) {
+		t.Fatalf("unexpected leaves %v", leaves)
+	}
+
+	g = newExampleGraph(t)
+	if roots := g.Roots(); !reflect.DeepEqual(roots, []string{"Jonas"}) {
+		t.Fatalf("unexpected roots %v", roots)
+	}
+	if leaves := g.Leaves(); !reflect.DeepEqual(leaves, []string{"Barbara"}) {
+		t.Fatalf("unexpected leaves %v", leaves)
+	}
+}
+
+func TestGraphDegree(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d;
+	a -> c -> d;
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		id      string
+		in, out int
+	}{
+		{"a", 0, 2},
+		{"b", 1, 1},
+		{"d", 2, 0},
+		{"x", 0, 0},
+	}
+	for _, tt := range testCases {
+		if in := g.InDegree(tt.id); in != tt.in {
+			t.Fatalf("expected InDegree(%s) %d != %d", tt.id, tt.in, in)
+		}
+		if out := g.OutDegree(tt.id); out != tt.out {
+			t.Fatalf("expected OutDegree(%s) %d != %d", tt.id, tt.out, out)
+		}
+	}
+}
+
+func TestGraphAncestorsDescendants(t *testing.T) {
+	g := newExampleGraph(t)
+
+	testCases := []struct {
+		desc        string
+		id          string
+		ancestors   []string
+		descendants []string
+	}{
+		{
+			desc:        "root",
+			id:          "Jonas",
+			ancestors:   []string{},
+			descendants: []string{"Sophie", "Nick", "Barbara"},
+		},
+		{
+			desc:        "middle",
+			id:          "Nick",
+			ancestors:   []string{"Jonas", "Sophie"},
+			descendants: []string{"Barbara"},
+		},
+		{
+			desc: "missing",
+			id:   "Ruby",
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			if ancestors := g.Ancestors(tt.id); !reflect.DeepEqual(ancestors, tt.ancestors) {
+				t.Fatalf("expected ancestors %v != %v", tt.ancestors, ancestors)
+			}
+			if descendants := g.Descendants(tt.id); !reflect.DeepEqual(descendants, tt.descendants) {
+				t.Fatalf("expected descendants %v != %v", tt.descendants, descendants)
+			}
+		})
+	}
+}
+
+func TestGraphParentsChildren(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> c; b -> c; c -> e; c -> d;
+}`), toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc     string
+		id       string
+		parents  []string
+		children []string
+	}{
+		{desc: "root", id: "a", parents: []string{}, children: []string{"c"}},
+		{desc: "middle", id: "c", parents: []string{"a", "b"}, children: []string{"d", "e"}},
+		{desc: "missing", id: "f"},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			if parents := g.Parents(tt.id); !reflect.DeepEqual(parents, tt.parents) {
+				t.Fatalf("expected parents %v != %v", tt.parents, parents)
+			}
+			if children := g.Children(tt.id); !reflect.DeepEqual(children, tt.children) {
+				t.Fatalf("expected children %v != %v", tt.children, children)
+			}
+		})
+	}
+}
+
+func TestGraphCommonAncestors(t *testing.T) {
+	g := newRunGraph(t)
+
+	testCases := []struct {
+		desc      string
+		a, b      string
+		ancestors []string
+		lowest    string
+	}{
+		{
+			desc:      "siblings",
+			a:         "d",
+			b:         "e",
+			ancestors: []string{"a"},
+			lowest:    "a",
+		},
+		{
+			desc:      "same branch",
+			a:         "d",
+			b:         "f",
+			ancestors: []string{"a", "b"},
+			lowest:    "b",
+		},
+		{
+			desc:      "root",
+			a:         "a",
+			b:         "f",
+			ancestors: []string{},
+		},
+		{
+			desc: "missing",
+			a:    "a",
+			b:    "z",
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			if ancestors := g.CommonAncestors(tt.a, tt.b); !reflect.DeepEqual(ancestors, tt.ancestors) {
+				t.Fatalf("expected common ancestors %v != %v", tt.ancestors, ancestors)
+			}
+			lowest, ok := g.LowestCommonAncestor(tt.a, tt.b)
+			if lowest != tt.lowest || ok != (tt.lowest != "") {
+				t.Fatalf("expected lowest common ancestor %q != %q (%t)", tt.lowest, lowest, ok)
+			}
+		})
+	}
+}
+
+func TestGraphWalk(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d;
+	a -> c -> d;
+	b -> e;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc     string
+		walk     func(start string, fn func(id string) bool) error
+		stop     string
+		expected []string
+	}{
+		{"dfs", g.WalkDFS, "", []string{"a", "b", "d", "e", "c"}},
+		{"dfs early termination", g.WalkDFS, "d", []string{"a", "b", "d"}},
+		{"bfs", g.WalkBFS, "", []string{"a", "b", "c", "d", "e"}},
+		{"bfs early termination", g.WalkBFS, "c", []string{"a", "b", "c"}},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			visited := []string{}
+			err := tt.walk("a", func(id string) bool {
+				visited = append(visited, id)
+				return id != tt.stop
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(visited, tt.expected) {
+				t.Fatalf("expected visits %v != %v", tt.expected, visited)
+			}
+			if err := tt.walk("z", func(string) bool { return true }); !errors.Is(err, toposort.ErrNotFound) {
+				t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+			}
+		})
+	}
+}
+
+func TestGraphReachable(t *testing.T) {
+	g := newExampleGraph(t)
+
+	testCases := []struct {
+		from, to  string
+		reachable bool
+	}{
+		{"Jonas", "Barbara", true},
+		{"Sophie", "Nick", true},
+		{"Barbara", "Jonas", false},
+		{"Nick", "Nick", true},
+		{"Ruby", "Ruby", false},
+		{"Jonas", "Ruby", false},
+	}
+	for i := 0; i < 2; i++ { // once more from the memoized sets
+		for _, tt := range testCases {
+			if reachable := g.Reachable(tt.from, tt.to); reachable != tt.reachable {
+				t.Fatalf("expected Reachable(%s, %s) %v != %v", tt.from, tt.to, tt.reachable, reachable)
+			}
+		}
+	}
+}
+
+func TestGraphTransitiveClosure(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d;
+	a -> c -> d -> e;
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closure := g.TransitiveClosure()
+	if len(closure) != 5 {
+		t.Fatalf("expected 5 keys in closure, got %v", closure)
+	}
+	for _, id := range g.Vertices() {
+		if !reflect.DeepEqual(closure[id], g.Descendants(id)) {
+			t.Fatalf("expected closure of %s %v != %v", id, g.Descendants(id), closure[id])
+		}
+	}
+}
+
+func TestGraphSubgraph(t *testing.T) {
+	g := newExampleGraph(t)
+
+	sub, err := g.Subgraph("Sophie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"Sophie", "Nick", "Barbara"}
+	if sorted := sub.SortedIDs(); !reflect.DeepEqual(sorted, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, sorted)
+	}
+
+	if _, err := g.Subgraph("Ruby"); !errors.Is(err, toposort.ErrNotFound) {
+		t.Fatalf("expected error %v != %v", toposort.ErrNotFound, err)
+	}
+
+	g, err = toposort.NewGraph(map[string]string{"b": "a", "c": "a", "d": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err = g.Subgraph("b", "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roots := sub.Roots(); len(roots) != 2 {
+		t.Fatalf("expected 2 roots, got %v", roots)
+	}
+	if n := sub.Len(); n != 3 {
+		t.Fatalf("expected 3 keys, got %d", n)
+	}
+}
@@ -0,0 +1,60 @@
+package toposort
+
+import "context"
+
+// Result is the outcome of sorting relations, kept even when they're
+// rejected, so that a tool can still make use of the order computed along
+// the way, or report the cycles and roots found all at once.
+type Result[K comparable] struct {
+	// Graph is the sorted graph, or nil if the relations were rejected.
+	Graph *Graph[K]
+	// Order lists the keys in topological order. If there are cycles, it
+	// respects every edge but the last one of each path of Cycles.
+	Order []K
+	// Cycles lists the paths of the cycles found, as in CycleError.
+	Cycles [][]K
+	// Roots lists the keys that don't depend on any other key, in order.
+	Roots []K
+	// Isolated lists the keys without any dependency nor dependent, in
+	// order.
+	Isolated []K
+	// Warnings lists the errors downgraded to warnings by WithWarnings.
+	Warnings []error
+}
+
+// Analyze sorts relations like NewGraph, returning a Result along with the
+// error NewGraph would return. The result is filled in even if relations are
+// rejected for cycles, multiple roots or invalid names, and is nil only for
+// the errors that keep relations from being sorted at all, like duplicate
+// edges with WithStrictEdges.
+func Analyze[K comparable](relations map[K]K, opts ...Option) (*Result[K], error) {
+	return AnalyzeContext(context.Background(), relations, opts...)
+}
+
+// AnalyzeContext is like Analyze, but gives up and returns the context's
+// error as soon as ctx is done.
+func AnalyzeContext[K comparable](ctx context.Context, relations map[K]K, opts ...Option) (*Result[K], error) {
+	ids, edges := relationEdges(relations)
+
+	g, errs, err := analyze(ctx, ids, edges, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Result[K]{
+		Order:    g.keys(g.sorted),
+		Cycles:   make([][]K, len(g.cycles)),
+		Roots:    g.Roots(),
+		Isolated: g.filter(func(v int) bool { return len(g.befores[v]) == 0 && len(g.afters[v]) == 0 }),
+		Warnings: g.Warnings(),
+	}
+	for i, path := range g.cycles {
+		r.Cycles[i] = g.keys(path)
+	}
+	if errs != nil {
+		return r, errs
+	}
+	r.Graph = g
+
+	return r, nil
+}
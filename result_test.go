@@ -0,0 +1,56 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestAnalyze(t *testing.T) {
+	r, err := toposort.Analyze(map[string]string{"b": "a", "d": "c"})
+	if !errors.Is(err, toposort.ErrMultipleRoots) {
+		t.Fatalf("expected error %v != %v", toposort.ErrMultipleRoots, err)
+	}
+	if r.Graph != nil {
+		t.Fatal("expected no graph")
+	}
+	if expected := []string{"a", "c"}; !sameKeys(r.Roots, expected) {
+		t.Fatalf("expected roots %v != %v", expected, r.Roots)
+	}
+	if len(r.Order) != 4 || len(r.Cycles) != 0 {
+		t.Fatalf("expected an order of the 4 keys and no cycles, got %v and %v", r.Order, r.Cycles)
+	}
+
+	r, err = toposort.Analyze(map[string]string{"b": "a", "c": "b", "a": "c", "d": "a"})
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+	if len(r.Cycles) != 1 || len(r.Cycles[0]) != 4 {
+		t.Fatalf("expected a cycle of 3 keys, got %v", r.Cycles)
+	}
+	index := map[string]int{}
+	for i, id := range r.Order {
+		index[id] = i
+	}
+	if index["a"] > index["d"] {
+		t.Fatalf("expected a before d in %v", r.Order)
+	}
+
+	r, err = toposort.Analyze(map[string]string{"b": "a", "c": "c"}, toposort.WithCyclePolicy(toposort.CycleIgnore))
+	if err == nil || r.Graph != nil {
+		t.Fatalf("expected multiple roots, got %v", err)
+	}
+	if expected := []string{"c"}; !reflect.DeepEqual(r.Isolated, expected) {
+		t.Fatalf("expected isolated keys %v != %v", expected, r.Isolated)
+	}
+
+	r, err = toposort.Analyze(map[string]string{"b": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Graph.SortedIDs(), r.Order) {
+		t.Fatalf("expected order %v != %v", r.Graph.SortedIDs(), r.Order)
+	}
+}
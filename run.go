@@ -0,0 +1,321 @@
+package toposort
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// ErrSkipped is raised for the keys left out by Run because a key they
+// depend on failed, with the SkipDescendants failure policy.
+var ErrSkipped = errors.New("skipped")
+
+// RunOption configures how Run executes a graph.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	concurrency int           // maximum number of keys processed at once
+	policy      FailurePolicy // what to do after a failure
+	retries     int           // attempts after the first failure of a key
+	keyRetries  any           // func(K) int overriding retries for each key
+	backoff     time.Duration // delay before the first retry, doubled after each retry
+	hook        any           // func(RunEvent[K]) notified of progress
+}
+
+// FailurePolicy tells Run what to do after a key fails.
+type FailurePolicy int
+
+const (
+	// FailFast stops starting keys at the first failure and cancels the
+	// context passed to the calls in progress. It's the default.
+	FailFast FailurePolicy = iota
+	// ContinueIndependent keeps processing the keys that don't depend on a
+	// failed key, leaving out the others silently.
+	ContinueIndependent
+	// SkipDescendants is like ContinueIndependent, but reports each key left
+	// out with an error wrapping ErrSkipped.
+	SkipDescendants
+)
+
+// WithConcurrency limits the number of keys processed at once to n. It
+// defaults to runtime.GOMAXPROCS(0), and values less than 1 are treated as 1.
+func WithConcurrency(n int) RunOption {
+	return func(o *runOptions) {
+		if n < 1 {
+			n = 1
+		}
+		o.concurrency = n
+	}
+}
+
+// WithContinueOnError makes Run keep processing the keys that don't depend on
+// a failed key, instead of stopping at the first failure. It's the same as
+// WithFailurePolicy(ContinueIndependent).
+func WithContinueOnError() RunOption {
+	return WithFailurePolicy(ContinueIndependent)
+}
+
+// WithFailurePolicy sets what Run does after a key fails, once its retries
+// are exhausted.
+func WithFailurePolicy(policy FailurePolicy) RunOption {
+	return func(o *runOptions) {
+		o.policy = policy
+	}
+}
+
+// WithRetries makes Run call fn again up to n times for a key that fails,
+// waiting backoff before the first retry and twice as long before each of
+// the next ones, until ctx is done.
+func WithRetries(n int, backoff time.Duration) RunOption {
+	return func(o *runOptions) {
+		o.retries, o.backoff = n, backoff
+	}
+}
+
+// WithKeyRetries overrides the number of retries of WithRetries for each key
+// with the number returned by retries, like for keys known to be flaky.
+//
+// The key type of retries must match the key type of the graph.
+func WithKeyRetries[K comparable](retries func(id K) int) RunOption {
+	return func(o *runOptions) {
+		o.keyRetries = retries
+	}
+}
+
+// EventKind is the kind of a RunEvent.
+type EventKind int
+
+const (
+	// EventStarted is sent when a key is started.
+	EventStarted EventKind = iota
+	// EventRetrying is sent when a failed key is about to be retried, with
+	// the error of the failed attempt.
+	EventRetrying
+	// EventFinished is sent when a key has completed successfully.
+	EventFinished
+	// EventFailed is sent when a key has failed for good, with its error.
+	EventFailed
+	// EventSkipped is sent for each key left out after a failure, or because
+	// ctx is done, once Run is over.
+	EventSkipped
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventStarted:
+		return "started"
+	case EventRetrying:
+		return "retrying"
+	case EventFinished:
+		return "finished"
+	case EventFailed:
+		return "failed"
+	case EventSkipped:
+		return "skipped"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// RunEvent reports the progress of Run for a key.
+type RunEvent[K comparable] struct {
+	Kind    EventKind
+	ID      K
+	Attempt int           // attempts made so far, 0 before the first one
+	Elapsed time.Duration // time since the key was started
+	Err     error         // error of the last attempt, if it failed
+}
+
+// WithEventHook makes Run call hook as keys are started, retried, finished,
+// failed or skipped, so that progress can be streamed to logs or a UI
+// without wrapping fn. Events of different keys are sent concurrently, so
+// hook must be safe for concurrent use.
+//
+// The key type of hook must match the key type of the graph.
+func WithEventHook[K comparable](hook func(e RunEvent[K])) RunOption {
+	return func(o *runOptions) {
+		o.hook = hook
+	}
+}
+
+// Run calls fn for every key of the graph in dependency order, starting a key
+// only after all the keys it depends on have completed successfully. Keys
+// that don't depend on each other are processed concurrently.
+//
+// A failed key is retried as set by WithRetries, and what happens next
+// depends on the failure policy, FailFast by default. Run waits for the calls
+// in progress before returning a MultiError of the failures, each wrapped
+// with its key, or nil if every key has been processed.
+func (g *Graph[K]) Run(ctx context.Context, fn func(ctx context.Context, id K) error, opts ...RunOption) error {
+	o := runOptions{concurrency: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	retries := func(K) int { return o.retries }
+	if o.keyRetries != nil {
+		var ok bool
+		if retries, ok = o.keyRetries.(func(K) int); !ok {
+			return fmt.Errorf("key retries %T does not accept %T keys", o.keyRetries, *new(K))
+		}
+	}
+	hook := func(RunEvent[K]) {}
+	if o.hook != nil {
+		var ok bool
+		if hook, ok = o.hook.(func(RunEvent[K])); !ok {
+			return fmt.Errorf("event hook %T does not accept %T keys", o.hook, *new(K))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		v   int
+		err error
+	}
+
+	indegree := make([]int, len(g.ids))
+	ready := []int{}
+	for _, v := range g.sorted {
+		if indegree[v] = len(g.befores[v]); indegree[v] == 0 {
+			ready = append(ready, v)
+		}
+	}
+
+	var (
+		err     MultiError
+		results = make(chan result)
+		running = 0
+		stopped = false
+		started = make([]bool, len(g.ids))
+		skipped = make([]bool, len(g.ids))
+	)
+
+	for {
+		for len(ready) > 0 && running < o.concurrency && !stopped {
+			if e := ctx.Err(); e != nil {
+				err = append(err, e)
+				stopped = true
+				break
+			}
+			v := ready[0]
+			ready = ready[1:]
+			running++
+			started[v] = true
+			go func() {
+				results <- result{v, attempt(ctx, fn, g.ids[v], retries(g.ids[v]), o.backoff, hook)}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+
+		if r.err != nil {
+			err = append(err, fmt.Errorf("%v: %w", g.ids[r.v], r.err))
+			switch o.policy {
+			case FailFast:
+				stopped = true
+				cancel()
+			case SkipDescendants:
+				for _, w := range g.walk(r.v, g.afters) {
+					if !skipped[w] {
+						skipped[w] = true
+						err = append(err, fmt.Errorf("%v: %w: %v failed", g.ids[w], ErrSkipped, g.ids[r.v]))
+					}
+				}
+			}
+			continue
+		}
+
+		for _, after := range g.afters[r.v] {
+			indegree[after]--
+			if indegree[after] == 0 {
+				ready = append(ready, after)
+			}
+		}
+	}
+
+	for _, v := range g.sorted {
+		if !started[v] {
+			hook(RunEvent[K]{Kind: EventSkipped, ID: g.ids[v]})
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// attempt calls fn for id, and then again up to retries times while it fails,
+// waiting backoff before the first retry and twice as long before each of the
+// next ones, unless ctx is done. The progress is reported to hook.
+func attempt[K comparable](ctx context.Context, fn func(ctx context.Context, id K) error, id K, retries int, backoff time.Duration, hook func(RunEvent[K])) error {
+	start := time.Now()
+	hook(RunEvent[K]{Kind: EventStarted, ID: id})
+
+	err := fn(ctx, id)
+	n := 1
+	for ; err != nil && n <= retries; n++ {
+		hook(RunEvent[K]{Kind: EventRetrying, ID: id, Attempt: n, Elapsed: time.Since(start), Err: err})
+		if !sleep(ctx, backoff<<(n-1)) {
+			break
+		}
+		err = fn(ctx, id)
+	}
+
+	e := RunEvent[K]{Kind: EventFinished, ID: id, Attempt: n, Elapsed: time.Since(start)}
+	if err != nil {
+		e.Kind, e.Err = EventFailed, err
+	}
+	hook(e)
+
+	return err
+}
+
+// Plan returns the stages of an execution plan of the graph, for callers
+// running the keys with their own runner, like an errgroup per stage. Every
+// key only depends on keys of earlier stages, so the keys of a stage can be
+// processed concurrently once the previous stages have completed. The stages
+// are the levels of Layers.
+func (g *Graph[K]) Plan() [][]K {
+	return g.Layers()
+}
+
+// ExecutePlan calls fn with each stage of Plan in order, starting a stage
+// only after the previous one has completed successfully. It stops at the
+// first failure, or when ctx is done, and returns the error wrapped with the
+// index of the stage.
+//
+// Unlike Run, a stage waits for its slowest key, but fn is free to fan out
+// the keys of a stage as it sees fit.
+func (g *Graph[K]) ExecutePlan(ctx context.Context, fn func(ctx context.Context, stage []K) error) error {
+	for i, stage := range g.Plan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(ctx, stage); err != nil {
+			return fmt.Errorf("stage %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// sleep waits for d, and reports whether it did before ctx was done.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,243 @@
+package toposort_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onur1/toposort"
+)
+
+func newRunGraph(t *testing.T) *toposort.Graph[string] {
+	t.Helper()
+
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> d -> f;
+	a -> c -> e -> f;
+}`), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+func TestGraphRun(t *testing.T) {
+	g := newRunGraph(t)
+
+	var (
+		mu       sync.Mutex
+		done     = make(map[string]bool)
+		parents  = map[string][]string{"b": {"a"}, "c": {"a"}, "d": {"b"}, "e": {"c"}, "f": {"d", "e"}}
+		active   int32
+		maxSeen  int32
+		order    []string
+		failures []string
+	)
+
+	err := g.Run(context.Background(), func(ctx context.Context, id string) error {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if n > maxSeen {
+			maxSeen = n
+		}
+		for _, p := range parents[id] {
+			if !done[p] {
+				failures = append(failures, id)
+			}
+		}
+		done[id] = true
+		order = append(order, id)
+		return nil
+	}, toposort.WithConcurrency(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 6 {
+		t.Fatalf("expected 6 keys to run, got %v", order)
+	}
+	if len(failures) > 0 {
+		t.Fatalf("keys ran before their dependencies: %v", failures)
+	}
+	if maxSeen > 2 {
+		t.Fatalf("expected at most 2 concurrent calls, got %d", maxSeen)
+	}
+}
+
+func TestGraphRunError(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	testCases := []struct {
+		desc    string
+		opts    []toposort.RunOption
+		ran     []string
+		skipped int
+	}{
+		{
+			desc: "stop",
+			opts: []toposort.RunOption{toposort.WithConcurrency(1)},
+			ran:  []string{"a", "b"},
+		},
+		{
+			desc: "continue",
+			opts: []toposort.RunOption{toposort.WithConcurrency(1), toposort.WithContinueOnError()},
+			ran:  []string{"a", "b", "c", "e"},
+		},
+		{
+			desc:    "skip descendants",
+			opts:    []toposort.RunOption{toposort.WithConcurrency(1), toposort.WithFailurePolicy(toposort.SkipDescendants)},
+			ran:     []string{"a", "b", "c", "e"},
+			skipped: 2,
+		},
+		{
+			desc: "retries",
+			opts: []toposort.RunOption{toposort.WithConcurrency(1), toposort.WithRetries(2, time.Millisecond)},
+			ran:  []string{"a", "b", "b", "b"},
+		},
+		{
+			desc: "key retries",
+			opts: []toposort.RunOption{
+				toposort.WithConcurrency(1),
+				toposort.WithRetries(2, 0),
+				toposort.WithKeyRetries(func(id string) int { return 1 }),
+			},
+			ran: []string{"a", "b", "b"},
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			g := newRunGraph(t)
+
+			var ran []string
+			err := g.Run(context.Background(), func(ctx context.Context, id string) error {
+				ran = append(ran, id)
+				if id == "b" {
+					return errFailed
+				}
+				return nil
+			}, tt.opts...)
+			if !errors.Is(err, errFailed) {
+				t.Fatalf("expected error %v != %v", errFailed, err)
+			}
+			if !sameKeys(ran, tt.ran) {
+				t.Fatalf("expected keys %v to run, got %v", tt.ran, ran)
+			}
+			var m toposort.MultiError
+			if !errors.As(err, &m) || len(m) != 1+tt.skipped {
+				t.Fatalf("expected %d errors, got %v", 1+tt.skipped, err)
+			}
+			if tt.skipped > 0 && !errors.Is(err, toposort.ErrSkipped) {
+				t.Fatalf("expected error %v != %v", toposort.ErrSkipped, err)
+			}
+		})
+	}
+}
+
+func TestGraphExecutePlan(t *testing.T) {
+	g := newRunGraph(t)
+
+	expected := [][]string{{"a"}, {"b", "c"}, {"d", "e"}, {"f"}}
+	if plan := g.Plan(); !sameStages(plan, expected) {
+		t.Fatalf("expected plan %v != %v", expected, plan)
+	}
+
+	stages := [][]string{}
+	err := g.ExecutePlan(context.Background(), func(ctx context.Context, stage []string) error {
+		stages = append(stages, stage)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameStages(stages, expected) {
+		t.Fatalf("expected stages %v != %v", expected, stages)
+	}
+
+	failure := errors.New("failure")
+	n := 0
+	err = g.ExecutePlan(context.Background(), func(ctx context.Context, stage []string) error {
+		if n++; n == 2 {
+			return failure
+		}
+		return nil
+	})
+	if !errors.Is(err, failure) || err.Error() != "stage 1: failure" {
+		t.Fatalf("expected error %v, got %v", failure, err)
+	}
+	if n != 2 {
+		t.Fatalf("expected to stop after the failed stage, ran %d", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.ExecutePlan(ctx, func(ctx context.Context, stage []string) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error %v != %v", context.Canceled, err)
+	}
+}
+
+// sameStages reports whether a and b hold the same keys in each stage.
+func sameStages(a, b [][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameKeys(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGraphRunEventHook(t *testing.T) {
+	g := newRunGraph(t)
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	err := g.Run(context.Background(), func(ctx context.Context, id string) error {
+		if id == "b" {
+			return errors.New("failed")
+		}
+		return nil
+	},
+		toposort.WithConcurrency(1),
+		toposort.WithRetries(1, 0),
+		toposort.WithFailurePolicy(toposort.ContinueIndependent),
+		toposort.WithEventHook(func(e toposort.RunEvent[string]) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, fmt.Sprintf("%s %s %d", e.ID, e.Kind, e.Attempt))
+		}),
+	)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	expected := []string{
+		"a started 0", "a finished 1",
+		"b started 0", "b retrying 1", "b failed 2",
+		"c started 0", "c finished 1",
+		"e started 0", "e finished 1",
+		"d skipped 0", "f skipped 0",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v != %v", expected, events)
+	}
+
+	err = g.Run(context.Background(), func(ctx context.Context, id string) error { return nil }, toposort.WithEventHook(func(e toposort.RunEvent[int]) {}))
+	if err == nil {
+		t.Fatal("expected an error for a hook of another key type")
+	}
+}
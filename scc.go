@@ -0,0 +1,264 @@
+package toposort
+
+import (
+	"context"
+	"sort"
+)
+
+// SCCs returns the strongly connected components of the graph in topological
+// order. Keys in a component of more than one key are on a cycle through each
+// other, whereas a graph without cycles only has components of a single key.
+func (g *Graph[K]) SCCs() [][]K {
+	sccs := tarjan(g.afters, g.sorted)
+
+	keys := make([][]K, len(sccs))
+	for i, scc := range sccs {
+		keys[len(sccs)-1-i] = g.keys(scc)
+	}
+
+	return keys
+}
+
+// Condense collapses each strongly connected component of relations into a
+// single key and sorts the resulting acyclic graph, so that a useful order can
+// still be obtained out of cyclic input. Each component is represented by its
+// smallest key, compared as with WithLexicographicOrder, and the returned map
+// lists the keys of the component of every representative.
+//
+// Keys are normalized with opts first, so that the components are made of
+// the keys of the returned graph. The returned error is the one NewGraph
+// would return for the condensed graph, with opts.
+func Condense[K comparable](relations map[K]K, opts ...Option) (*Graph[K], map[K][]K, error) {
+	o := newOptions(opts)
+	g, err := unsorted(relations, o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vs := make([]int, len(g.ids))
+	for v := range vs {
+		vs[v] = v
+	}
+
+	component := make([]int, len(g.ids))
+	members := make(map[K][]K)
+	ids := []K{}
+	for _, scc := range tarjan(g.afters, vs) {
+		keys := g.keys(scc)
+		sort.Slice(keys, func(i, j int) bool {
+			return lexicalLess(keys[i], keys[j])
+		})
+		for _, v := range scc {
+			component[v] = len(ids)
+		}
+		ids = append(ids, keys[0])
+		members[keys[0]] = keys
+	}
+
+	// keys are given as spelled in relations, to be normalized only once
+	spellings := make([]K, len(ids))
+	for i, id := range ids {
+		spellings[i] = g.spelling(id)
+	}
+	seen := make(map[[2]int]bool)
+	edges := [][2]K{}
+	for v := range g.afters {
+		for _, after := range g.afters[v] {
+			e := [2]int{component[v], component[after]}
+			if e[0] != e[1] && !seen[e] {
+				seen[e] = true
+				edges = append(edges, [2]K{spellings[e[0]], spellings[e[1]]})
+			}
+		}
+	}
+
+	cg, err := newGraph(context.Background(), spellings, edges, o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cg, members, nil
+}
+
+// PartialOrder sorts the part of relations that can be sorted despite their
+// cycles, so that a tool can act on what it safely can and set the rest
+// aside. Order lists the keys neither on a cycle nor depending on a key on a
+// cycle, sorted like NewGraph would with opts, and cyclic lists the other
+// keys, ordered by their strongly connected components in topological order.
+//
+// Keys are normalized with opts first, like the keys of a graph. The
+// returned error is the one NewGraph would return for the keys of order, with
+// opts.
+func PartialOrder[K comparable](relations map[K]K, opts ...Option) (order, cyclic []K, err error) {
+	o := newOptions(opts)
+	g, err := unsorted(relations, o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vs := make([]int, len(g.ids))
+	for v := range vs {
+		vs[v] = v
+	}
+
+	// keys on a cycle, and then the keys depending on them, are quarantined
+	quarantined := make([]bool, len(g.ids))
+	sccs := tarjan(g.afters, vs)
+	for _, scc := range sccs {
+		if len(scc) > 1 || contains(g.afters[scc[0]], scc[0]) {
+			for _, v := range scc {
+				quarantined[v] = true
+			}
+		}
+	}
+	for i := len(sccs) - 1; i >= 0; i-- { // in topological order
+		for _, v := range sccs[i] {
+			if !quarantined[v] {
+				continue
+			}
+			for _, after := range g.afters[v] {
+				quarantined[after] = true
+			}
+		}
+	}
+
+	// keys are given as spelled in relations, to be normalized only once
+	ids, edges := []K{}, [][2]K{}
+	for v, id := range g.ids {
+		if quarantined[v] {
+			continue
+		}
+		ids = append(ids, g.spelling(id))
+		for _, after := range g.afters[v] {
+			if !quarantined[after] {
+				edges = append(edges, [2]K{g.spelling(id), g.spelling(g.ids[after])})
+			}
+		}
+	}
+	cyclic = []K{}
+	for i := len(sccs) - 1; i >= 0; i-- {
+		if quarantined[sccs[i][0]] {
+			cyclic = append(cyclic, g.keys(sccs[i])...)
+		}
+	}
+
+	sg, err := newGraph(context.Background(), ids, edges, o)
+	if err != nil {
+		return nil, cyclic, err
+	}
+
+	return sg.Vertices(), cyclic, nil
+}
+
+// Components returns the weakly connected components of the graph, the sets
+// of keys linked by edges regardless of their direction. Keys of each
+// component are in topological order, and components are ordered by their
+// first key.
+func (g *Graph[K]) Components() [][]K {
+	component := make([]int, len(g.ids))
+	for v := range component {
+		component[v] = -1
+	}
+
+	n := 0
+	for _, v := range g.sorted {
+		if component[v] >= 0 {
+			continue
+		}
+		component[v] = n
+		stack := []int{v}
+		for len(stack) > 0 {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, edges := range [][]int{g.afters[w], g.befores[w]} {
+				for _, u := range edges {
+					if component[u] < 0 {
+						component[u] = n
+						stack = append(stack, u)
+					}
+				}
+			}
+		}
+		n++
+	}
+
+	keys := make([][]K, n)
+	for _, v := range g.sorted {
+		keys[component[v]] = append(keys[component[v]], g.ids[v])
+	}
+
+	return keys
+}
+
+// tarjan finds the strongly connected components of the given graph, where
+// afters lists the vertices depending on each vertex, with Tarjan's
+// algorithm, visiting the vertices in vs order. Components are returned in
+// reverse topological order.
+func tarjan(afters [][]int, vs []int) (sccs [][]int) {
+	index := make([]int, len(afters))
+	low := make([]int, len(afters))
+	visited := make([]bool, len(afters))
+	onStack := make([]bool, len(afters))
+	stack := []int{}
+
+	type frame struct {
+		v    int // vertex being visited
+		next int // index of the next edge to follow
+	}
+
+	n := 0
+	push := func(v int) {
+		index[v], low[v] = n, n
+		n++
+		visited[v] = true
+		stack = append(stack, v)
+		onStack[v] = true
+	}
+
+	for _, k := range vs {
+		if visited[k] {
+			continue
+		}
+		push(k)
+		calls := []frame{{v: k}}
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			if top.next < len(afters[top.v]) {
+				after := afters[top.v][top.next]
+				top.next++
+				if !visited[after] {
+					push(after)
+					calls = append(calls, frame{v: after})
+				} else if onStack[after] && index[after] < low[top.v] {
+					low[top.v] = index[after]
+				}
+				continue
+			}
+
+			v := top.v
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				if parent := calls[len(calls)-1].v; low[v] < low[parent] {
+					low[parent] = low[v]
+				}
+			}
+			if low[v] != index[v] {
+				continue
+			}
+
+			// v is the root of a component, pop it off the stack
+			i := len(stack) - 1
+			for stack[i] != v {
+				i--
+			}
+			scc := append([]int{}, stack[i:]...)
+			for _, w := range scc {
+				onStack[w] = false
+			}
+			stack = stack[:i]
+			sccs = append(sccs, scc)
+		}
+	}
+
+	return
+}
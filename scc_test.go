@@ -0,0 +1,171 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphSCCs(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"Barbara": "Nick",
+		"Nick":    "Sophie",
+		"Sophie":  "Jonas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{{"Jonas"}, {"Sophie"}, {"Nick"}, {"Barbara"}}
+	if sccs := g.SCCs(); !reflect.DeepEqual(sccs, expected) {
+		t.Fatalf("expected components %v != %v", expected, sccs)
+	}
+}
+
+func TestGraphOverlappingCycles(t *testing.T) {
+	_, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	x -> a -> b -> a;
+	b -> c -> b;
+}`))
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+
+	n := 0
+	for _, e := range err.(toposort.MultiError) {
+		if errors.Is(e, toposort.ErrCircular) {
+			n++
+		}
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 cycles, got %v", err)
+	}
+}
+
+func TestGraphComponents(t *testing.T) {
+	dot := `digraph {
+	a -> c;
+	b -> c;
+	d -> e;
+	f;
+}`
+	g, err := toposort.ParseDOT(strings.NewReader(dot), toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{{"a", "b", "c"}, {"d", "e"}, {"f"}}
+	if components := g.Components(); !reflect.DeepEqual(components, expected) {
+		t.Fatalf("expected components %v != %v", expected, components)
+	}
+
+	_, err = toposort.ParseDOT(strings.NewReader(dot), toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder(), toposort.WithConnected())
+	var de *toposort.DisconnectedError[string]
+	if !errors.As(err, &de) || !reflect.DeepEqual(de.Components, expected) {
+		t.Fatalf("expected disconnected error with components %v, got %v", expected, err)
+	}
+	if !errors.Is(err, toposort.ErrDisconnected) {
+		t.Fatalf("expected error %v != %v", toposort.ErrDisconnected, err)
+	}
+
+	_, err = toposort.ParseDOT(strings.NewReader(`digraph { a -> c; b -> c }`), toposort.WithAllowMultipleRoots(), toposort.WithConnected())
+	if err != nil {
+		t.Fatalf("expected two roots with a common dependent to be connected, got %v", err)
+	}
+}
+
+func TestCondense(t *testing.T) {
+	g, members, err := toposort.Condense(map[string]string{
+		"b": "a",
+		"c": "b",
+		"a": "c",
+		"d": "c",
+		"e": "e",
+		"f": "e",
+		"g": "d",
+	}, toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"a", "d", "e", "f", "g"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, g.SortedIDs())
+	}
+	if expected := [][2]string{{"a", "d"}, {"d", "g"}, {"e", "f"}}; !reflect.DeepEqual(g.Edges(), expected) {
+		t.Fatalf("expected edges %v != %v", expected, g.Edges())
+	}
+	expected := map[string][]string{
+		"a": {"a", "b", "c"},
+		"d": {"d"},
+		"e": {"e"},
+		"f": {"f"},
+		"g": {"g"},
+	}
+	if !reflect.DeepEqual(members, expected) {
+		t.Fatalf("expected members %v != %v", expected, members)
+	}
+
+	g, members, err = toposort.Condense(map[string]string{"A": "b", "B": "a"}, toposort.WithCaseFolding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, g.SortedIDs())
+	}
+	if expected := map[string][]string{"a": {"a", "b"}}; !reflect.DeepEqual(members, expected) {
+		t.Fatalf("expected members %v != %v", expected, members)
+	}
+	if original, _ := g.OriginalID("a"); original != "A" {
+		t.Fatalf("expected a to be spelled A, got %s", original)
+	}
+}
+
+func TestPartialOrder(t *testing.T) {
+	order, cyclic, err := toposort.PartialOrder(map[string]string{
+		"b": "a",
+		"c": "b",
+		"a": "c",
+		"d": "c",
+		"e": "e",
+		"y": "x",
+		"z": "y",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"x", "y", "z"}; !reflect.DeepEqual(order, expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, order)
+	}
+	if expected := []string{"a", "b", "c", "d", "e"}; !sameKeys(cyclic, expected) {
+		t.Fatalf("expected cyclic keys %v != %v", expected, cyclic)
+	}
+	for i, id := range cyclic {
+		if id == "d" && i < 3 {
+			t.Fatalf("expected d to come after its cycle in %v", cyclic)
+		}
+	}
+
+	_, _, err = toposort.PartialOrder(map[string]string{"b": "a", "d": "c"})
+	if !errors.Is(err, toposort.ErrMultipleRoots) {
+		t.Fatalf("expected error %v != %v", toposort.ErrMultipleRoots, err)
+	}
+
+	order, cyclic, err = toposort.PartialOrder(map[string]string{
+		"A": "b",
+		"B": "a",
+		"c": "X",
+	}, toposort.WithCaseFolding(), toposort.WithAllowMultipleRoots())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"x", "c"}; !reflect.DeepEqual(order, expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, order)
+	}
+	if expected := []string{"a", "b"}; !sameKeys(cyclic, expected) {
+		t.Fatalf("expected cyclic keys %v != %v", expected, cyclic)
+	}
+}
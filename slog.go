@@ -0,0 +1,16 @@
+//go:build go1.21
+
+package toposort
+
+import "log/slog"
+
+// WithLogger makes the graph log the steps of its construction at debug
+// level to logger, like the cycles found and the edges discarded, so that
+// bad input can be diagnosed from within a server.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
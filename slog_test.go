@@ -0,0 +1,48 @@
+//go:build go1.21
+
+package toposort_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestWithLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err := toposort.NewGraph(map[string]string{
+		"b": "a",
+		"a": "b",
+		"d": "c",
+	}, toposort.WithLogger(logger), toposort.WithCyclePolicy(toposort.CycleIgnore), toposort.WithWarnings(toposort.ErrMultipleRoots))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range []string{
+		`msg="graph built" keys=4 edges=3 duplicates=0`,
+		`msg="edge dropped to break a cycle"`,
+		`msg="error downgraded to a warning"`,
+	} {
+		if !strings.Contains(b.String(), msg) {
+			t.Fatalf("expected %s in log %s", msg, b.String())
+		}
+	}
+
+	b.Reset()
+	if _, err := toposort.NewGraph(map[string]string{"a": "a"}, toposort.WithLogger(logger)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if expected := `msg="cycle found" path="[a a]"`; !strings.Contains(b.String(), expected) {
+		t.Fatalf("expected %s in log %s", expected, b.String())
+	}
+
+	if _, err := toposort.NewGraph(map[string]string{"b": "a"}, toposort.WithLogger(nil)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,93 @@
+package toposort
+
+import "sync"
+
+// SyncGraph guards a graph with a read-write mutex, so that it can be queried
+// and mutated from many goroutines.
+//
+// The most common queries and mutations are provided as methods, and the
+// others can be called on the graph passed to Read or Update.
+type SyncGraph[K comparable] struct {
+	mu sync.RWMutex
+	g  *Graph[K]
+}
+
+// NewSyncGraph returns a SyncGraph guarding g, which must not be used
+// directly afterwards.
+func NewSyncGraph[K comparable](g *Graph[K]) *SyncGraph[K] {
+	return &SyncGraph[K]{g: g}
+}
+
+// Read calls fn with the graph locked for reading. fn must not mutate the
+// graph, nor keep it after returning.
+func (s *SyncGraph[K]) Read(fn func(g *Graph[K])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.g)
+}
+
+// Update calls fn with the graph locked for writing, and returns its error.
+// fn must not keep the graph after returning.
+func (s *SyncGraph[K]) Update(fn func(g *Graph[K]) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.g)
+}
+
+// Snapshot returns an independent copy of the graph. See Graph.Clone.
+func (s *SyncGraph[K]) Snapshot() (g *Graph[K]) {
+	s.Read(func(sg *Graph[K]) { g = sg.Clone() })
+	return
+}
+
+// SortedIDs returns the keys of the graph in topological order.
+func (s *SyncGraph[K]) SortedIDs() (ids []K) {
+	s.Read(func(g *Graph[K]) { ids = g.SortedIDs() })
+	return
+}
+
+// Index returns the position of id in the topological order, or -1 if id is
+// not in the graph.
+func (s *SyncGraph[K]) Index(id K) (i int) {
+	s.Read(func(g *Graph[K]) { i = g.Index(id) })
+	return
+}
+
+// Before reports whether a precedes b in the topological order.
+func (s *SyncGraph[K]) Before(a, b K) (ok bool) {
+	s.Read(func(g *Graph[K]) { ok = g.Before(a, b) })
+	return
+}
+
+// Ancestors returns the keys that id transitively depends on.
+func (s *SyncGraph[K]) Ancestors(id K) (ids []K) {
+	s.Read(func(g *Graph[K]) { ids = g.Ancestors(id) })
+	return
+}
+
+// Descendants returns the keys transitively depending on id.
+func (s *SyncGraph[K]) Descendants(id K) (ids []K) {
+	s.Read(func(g *Graph[K]) { ids = g.Descendants(id) })
+	return
+}
+
+// Reachable reports whether to transitively depends on from.
+func (s *SyncGraph[K]) Reachable(from, to K) (ok bool) {
+	s.Read(func(g *Graph[K]) { ok = g.Reachable(from, to) })
+	return
+}
+
+// AddEdge adds an edge from from to to. See Graph.AddEdge.
+func (s *SyncGraph[K]) AddEdge(from, to K) error {
+	return s.Update(func(g *Graph[K]) error { return g.AddEdge(from, to) })
+}
+
+// RemoveEdge removes the edge from from to to. See Graph.RemoveEdge.
+func (s *SyncGraph[K]) RemoveEdge(from, to K) error {
+	return s.Update(func(g *Graph[K]) error { return g.RemoveEdge(from, to) })
+}
+
+// RemoveVertex removes id and its edges. See Graph.RemoveVertex.
+func (s *SyncGraph[K]) RemoveVertex(id K) error {
+	return s.Update(func(g *Graph[K]) error { return g.RemoveVertex(id) })
+}
@@ -0,0 +1,56 @@
+package toposort_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestSyncGraph(t *testing.T) {
+	s := toposort.NewSyncGraph(newExampleGraph(t))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := s.AddEdge("Barbara", fmt.Sprint("Ruby", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if !s.Before("Jonas", "Barbara") || !s.Reachable("Jonas", "Barbara") {
+				t.Error("expected Barbara to depend on Jonas")
+			}
+			_ = s.Descendants("Nick")
+		}()
+	}
+	wg.Wait()
+
+	if n := len(s.Descendants("Barbara")); n != 8 {
+		t.Fatalf("expected 8 descendants, got %d", n)
+	}
+
+	snapshot := s.Snapshot()
+	if err := s.RemoveVertex("Ruby0"); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Index("Ruby0") < 0 {
+		t.Fatal("expected the snapshot to be left unchanged")
+	}
+
+	err := s.Update(func(g *toposort.Graph[string]) error {
+		return g.SetAttr("Nick", "age", 7)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Read(func(g *toposort.Graph[string]) {
+		if age, _ := g.Attr("Nick", "age"); age != 7 {
+			t.Errorf("expected age 7, got %v", age)
+		}
+	})
+}
@@ -0,0 +1,10 @@
+module github.com/onur1/toposort/textnorm
+
+go 1.20
+
+require (
+	github.com/onur1/toposort v0.1.1-0.20261016015701-248d6bfe3d5c
+	golang.org/x/text v0.22.0
+)
+
+replace github.com/onur1/toposort => ../
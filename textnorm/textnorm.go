@@ -0,0 +1,36 @@
+// Package textnorm provides options normalizing the string keys of graphs of
+// package toposort with golang.org/x/text, so that visually identical keys
+// made of different code points, or keys that only differ in case in a given
+// language, are the same key.
+//
+// It's a module of its own, so that package toposort doesn't depend on
+// golang.org/x/text.
+package textnorm
+
+import (
+	"github.com/onur1/toposort"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithUnicodeNormalization makes string keys normalized to the given Unicode
+// normalization form, like "é" and "e" followed by a combining acute accent,
+// which are the same key. norm.NFC suits most input, and norm.NFKC also
+// merges compatibility characters, like the "ﬁ" ligature and "fi".
+//
+// It runs after the other normalizers given before it, so it's given last to
+// normalize keys folded by WithCaseFoldingFor too.
+func WithUnicodeNormalization(form norm.Form) toposort.Option {
+	return toposort.WithNormalizer(form.String)
+}
+
+// WithCaseFoldingFor is like toposort.WithCaseFolding, which it's given
+// instead of, but lower cases keys with the rules of the given language, like
+// the dotless i of Turkish, where "I" is the upper case of "ı" rather than
+// "i".
+func WithCaseFoldingFor(tag language.Tag) toposort.Option {
+	return toposort.WithNormalizer(func(s string) string {
+		return cases.Lower(tag).String(s) // casers aren't safe for concurrent use
+	})
+}
@@ -0,0 +1,72 @@
+package textnorm_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+	"github.com/onur1/toposort/textnorm"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestWithUnicodeNormalization(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		relations map[string]string
+		form      norm.Form
+	}{
+		{
+			desc:      "NFC",
+			relations: map[string]string{"Ren\u00e9e": "Nick", "Nick": "Rene\u0301e"},
+			form:      norm.NFC,
+		},
+		{
+			desc:      "NFKC",
+			relations: map[string]string{"\ufb01le": "Nick", "Nick": "file"},
+			form:      norm.NFKC,
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := toposort.NewGraph(tt.relations); err != nil {
+				t.Fatal(err)
+			}
+			_, err := toposort.NewGraph(tt.relations, textnorm.WithUnicodeNormalization(tt.form))
+			if !errors.Is(err, toposort.ErrCircular) {
+				t.Fatalf("expected both spellings to be the same key, got %v", err)
+			}
+		})
+	}
+
+	if _, err := toposort.NewGraph(map[string]string{"\ufb01le": "file"}, textnorm.WithUnicodeNormalization(norm.NFC)); err != nil {
+		t.Fatalf("expected NFC to keep the ligature, got %v", err)
+	}
+}
+
+func TestWithCaseFoldingFor(t *testing.T) {
+	g, err := toposort.NewGraph(map[string]string{
+		"KIRMIZI":  "İstanbul",
+		"kırmızı2": "KIRMIZI",
+	}, textnorm.WithCaseFoldingFor(language.Turkish))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"istanbul", "kırmızı", "kırmızı2"}; !reflect.DeepEqual(g.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, g.SortedIDs())
+	}
+	if id, ok := g.CanonicalID("KIRMIZI2"); id != "kırmızı2" || !ok {
+		t.Fatalf("expected kırmızı2, got %q (%t)", id, ok)
+	}
+
+	g, err = toposort.NewGraph(map[string]string{"KIRMIZI": "İstanbul"}, toposort.WithCaseFolding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.CanonicalID("kırmızı"); ok {
+		t.Fatal("expected the dotless i to be kept apart without Turkish rules")
+	}
+}
@@ -0,0 +1,99 @@
+// Package toposortgen generates random inputs for package toposort, acyclic
+// or with cycles injected on purpose, for fuzzing and benchmarking code built
+// on top of it. Keys are the integers from 0 to n-1, and the same seed always
+// generates the same input.
+package toposortgen
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/onur1/toposort"
+)
+
+// CycleLabel is the label of the edges added by InjectCycles.
+const CycleLabel = "cycle"
+
+// DAG returns the edges of a random directed acyclic graph of n keys, where
+// each pair of keys is linked with probability density, pointing from the
+// key coming first in a random hidden order. Keys left without edges aren't
+// listed.
+//
+// Edges are drawn in time linear to their number, so that large sparse
+// graphs are cheap to generate.
+func DAG(n int, density float64, seed int64) []toposort.Edge[int] {
+	r := rand.New(rand.NewSource(seed))
+	perm := r.Perm(n)
+
+	edges := []toposort.Edge[int]{}
+	if density <= 0 || n < 2 {
+		return edges
+	}
+
+	// the pairs i < j are visited in order, skipping the pairs left out
+	// with a geometric distribution
+	skip := func() int {
+		if density >= 1 {
+			return 0
+		}
+		s := math.Log(1-r.Float64()) / math.Log(1-density)
+		if s > float64(n)*float64(n) {
+			return n * n
+		}
+		return int(s)
+	}
+	i, j := 0, 0
+	for {
+		j += 1 + skip()
+		for j >= n && i < n-1 {
+			j, i = j-n+i+2, i+1
+		}
+		if i >= n-1 {
+			break
+		}
+		edges = append(edges, toposort.Edge[int]{From: perm[i], To: perm[j]})
+	}
+
+	return edges
+}
+
+// Relations returns random relations of n keys making a single tree, as
+// given to toposort.NewGraph, where each key but the root depends on a key
+// picked at random among the keys coming before it in a random hidden order.
+func Relations(n int, seed int64) map[int]int {
+	r := rand.New(rand.NewSource(seed))
+	perm := r.Perm(n)
+
+	relations := make(map[int]int, n)
+	for i := 1; i < n; i++ {
+		relations[perm[i]] = perm[r.Intn(i)]
+	}
+
+	return relations
+}
+
+// InjectCycles returns a copy of edges with up to k edges added to close
+// cycles, labeled CycleLabel. Each of them points back from a key reached by
+// a random walk along edges to the key the walk started from, so that cycles
+// of various lengths are made. Fewer edges are added if edges has too few to
+// walk along.
+func InjectCycles(edges []toposort.Edge[int], k int, seed int64) []toposort.Edge[int] {
+	r := rand.New(rand.NewSource(seed))
+
+	afters := map[int][]int{}
+	for _, e := range edges {
+		afters[e.From] = append(afters[e.From], e.To)
+	}
+
+	injected := append([]toposort.Edge[int]{}, edges...)
+	for i := 0; i < k && len(edges) > 0; i++ {
+		e := edges[r.Intn(len(edges))]
+		to := e.To
+		for steps := r.Intn(4); steps > 0 && len(afters[to]) > 0; steps-- {
+			to = afters[to][r.Intn(len(afters[to]))]
+		}
+		injected = append(injected, toposort.Edge[int]{From: to, To: e.From, Label: CycleLabel})
+	}
+
+	return injected
+}
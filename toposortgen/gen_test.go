@@ -0,0 +1,72 @@
+package toposortgen_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/onur1/toposort"
+	"github.com/onur1/toposort/toposortgen"
+)
+
+func TestDAG(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		n       int
+		density float64
+		min     int
+		max     int
+	}{
+		{desc: "empty", n: 0, density: 0.5},
+		{desc: "no edges", n: 10, density: 0},
+		{desc: "complete", n: 10, density: 1, min: 45, max: 45},
+		{desc: "sparse", n: 1000, density: 0.01, min: 4000, max: 6000},
+	}
+	for _, tt := range testCases {
+		tt := tt
+
+		t.Run(tt.desc, func(t *testing.T) {
+			edges := toposortgen.DAG(tt.n, tt.density, 1)
+			if len(edges) < tt.min || len(edges) > tt.max {
+				t.Fatalf("expected between %d and %d edges, got %d", tt.min, tt.max, len(edges))
+			}
+			if !reflect.DeepEqual(toposortgen.DAG(tt.n, tt.density, 1), edges) {
+				t.Fatal("expected the same edges for the same seed")
+			}
+			if _, err := toposort.NewGraphFromEdges(edges, toposort.WithAllowMultipleRoots(), toposort.WithStrictEdges()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRelations(t *testing.T) {
+	g, err := toposort.NewGraph(toposortgen.Relations(100, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(g.Vertices()); n != 100 {
+		t.Fatalf("expected 100 keys, got %d", n)
+	}
+}
+
+func TestInjectCycles(t *testing.T) {
+	edges := toposortgen.DAG(50, 0.1, 1)
+
+	injected := toposortgen.InjectCycles(edges, 3, 1)
+	if len(injected) != len(edges)+3 {
+		t.Fatalf("expected 3 more edges, got %d", len(injected)-len(edges))
+	}
+	_, err := toposort.NewGraphFromEdges(injected, toposort.WithAllowMultipleRoots())
+	if !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+
+	g, err := toposort.NewGraphFromEdges(injected, toposort.WithAllowMultipleRoots(), toposort.WithOptionalLabels(toposortgen.CycleLabel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.DroppedEdges()) == 0 {
+		t.Fatal("expected injected edges to be dropped")
+	}
+}
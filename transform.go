@@ -0,0 +1,85 @@
+package toposort
+
+import "context"
+
+// Reverse returns the transposed graph, where every edge is flipped, sorted
+// on its own. The keys depending on a key in g are the keys it depends on in
+// the returned graph, so that its order is the reverse dependency order.
+//
+// As the leaves of g become roots, the returned graph allows multiple roots.
+func (g *Graph[K]) Reverse() *Graph[K] {
+	edges := g.Edges()
+	for i, e := range edges {
+		edges[i] = [2]K{e[1], e[0]}
+	}
+
+	return g.derive(g.SortedIDs(), edges)
+}
+
+// TransitiveReduction returns a graph with the same keys and order, without
+// the edges implied by others: an edge from a to c is removed when there's
+// also a path from a to c through other keys, like a → b → c. It's computed
+// from the transitive closure of g, held in V²/8 bytes for V keys like in
+// TransitiveClosure.
+func (g *Graph[K]) TransitiveReduction() *Graph[K] {
+	sets, _ := g.closure(context.Background())
+
+	edges := [][2]K{}
+	for i, v := range g.sorted {
+		implied := make([]uint64, len(sets[i]))
+		for _, after := range g.afters[v] {
+			for w, bits := range sets[g.position[after]] {
+				implied[w] |= bits
+			}
+		}
+		for _, after := range g.afters[v] {
+			if j := g.position[after]; implied[j/64]&(1<<(j%64)) == 0 {
+				edges = append(edges, [2]K{g.ids[v], g.ids[after]})
+				implied[j/64] |= 1 << (j % 64) // drop duplicates
+			}
+		}
+	}
+
+	return g.derive(g.SortedIDs(), edges)
+}
+
+// derive builds a graph with the options of g from the given keys and edges,
+// which are expected to form a valid graph of any shape. The keys are taken
+// as they are in g, without being normalized again.
+func (g *Graph[K]) derive(ids []K, edges [][2]K) *Graph[K] {
+	ng, err := newGraph(context.Background(), ids, edges, g.opts.relaxed())
+	if err != nil {
+		panic(err)
+	}
+	g.copyAttrs(ng)
+
+	return ng
+}
+
+// Merge returns the union of g and other, made of the keys and edges of both,
+// sorted and validated again with the options of g. The returned error is a
+// MultiError like the one of NewGraph, listing for instance the cycles closed
+// by combining edges of both graphs.
+//
+// Attributes of both graphs are kept, those of g winning for the same key.
+func (g *Graph[K]) Merge(other *Graph[K]) (*Graph[K], error) {
+	ids := append(g.SortedIDs(), other.SortedIDs()...)
+
+	seen := make(map[[2]K]bool)
+	edges := [][2]K{}
+	for _, e := range append(g.Edges(), other.Edges()...) {
+		if !seen[e] {
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+
+	ng, err := newGraph(context.Background(), ids, edges, g.opts)
+	if err != nil {
+		return nil, err
+	}
+	other.copyAttrs(ng)
+	g.copyAttrs(ng)
+
+	return ng, nil
+}
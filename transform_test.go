@@ -0,0 +1,121 @@
+package toposort_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphReverse(t *testing.T) {
+	g := newExampleGraph(t)
+
+	r := g.Reverse()
+
+	expected := []string{"Barbara", "Nick", "Sophie", "Jonas"}
+	if sorted := r.SortedIDs(); !reflect.DeepEqual(sorted, expected) {
+		t.Fatalf("expected sorted value %+v != %+v", expected, sorted)
+	}
+	if descendants := r.Descendants("Nick"); !reflect.DeepEqual(descendants, []string{"Sophie", "Jonas"}) {
+		t.Fatalf("unexpected descendants %v", descendants)
+	}
+}
+
+func TestGraphTransitiveReduction(t *testing.T) {
+	g, err := toposort.ParseDOT(strings.NewReader(`digraph {
+	a -> b -> c -> d;
+	a -> c;
+	a -> d;
+	b -> d;
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := g.TransitiveReduction()
+
+	expected := [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}}
+	if edges := r.Edges(); !reflect.DeepEqual(edges, expected) {
+		t.Fatalf("expected edges %v != %v", expected, edges)
+	}
+	if !reflect.DeepEqual(r.SortedIDs(), g.SortedIDs()) {
+		t.Fatalf("expected sorted value %+v != %+v", g.SortedIDs(), r.SortedIDs())
+	}
+}
+
+func TestGraphMerge(t *testing.T) {
+	a, err := toposort.NewGraph(map[string]string{"b": "a", "c": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := toposort.NewGraph(map[string]string{"d": "c", "c": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := a.Merge(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(m.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, m.SortedIDs())
+	}
+	if expected := [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}}; !reflect.DeepEqual(m.Edges(), expected) {
+		t.Fatalf("expected edges %v != %v", expected, m.Edges())
+	}
+
+	c, err := toposort.NewGraph(map[string]string{"a": "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Merge(c); !errors.Is(err, toposort.ErrCircular) {
+		t.Fatalf("expected error %v != %v", toposort.ErrCircular, err)
+	}
+}
+
+func TestGraphDeriveRelaxesShape(t *testing.T) {
+	connected, err := toposort.NewGraph(map[string]string{"b": "a", "c": "b"}, toposort.WithConnected())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := connected.AddVertex("zz"); err != nil {
+		t.Fatal(err)
+	}
+	if sorted := connected.Reverse().SortedIDs(); len(sorted) != 4 {
+		t.Fatalf("unexpected sorted value %+v", sorted)
+	}
+
+	chain, err := toposort.NewGraph(map[string]string{"b": "a", "c": "b"}, toposort.WithStrictChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.AddEdge("a", "zz"); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][2]string{{"a", "b"}, {"a", "zz"}, {"b", "c"}}
+	if edges := chain.TransitiveReduction().Edges(); !reflect.DeepEqual(edges, expected) {
+		t.Fatalf("expected edges %v != %v", expected, edges)
+	}
+}
+
+func TestGraphDeriveKeepsCanonicalKeys(t *testing.T) {
+	trim := func(s string) string { return strings.TrimSuffix(s, "b") }
+
+	g, err := toposort.NewGraph(map[string]string{"abb": "ab"}, toposort.WithNormalizer(trim), toposort.WithStrictIDs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := g.Reverse()
+	if expected := []string{"ab", "a"}; !reflect.DeepEqual(r.SortedIDs(), expected) {
+		t.Fatalf("expected sorted value %v != %v", expected, r.SortedIDs())
+	}
+	if original, _ := r.OriginalID("abb"); r.Index("abb") != 0 || original != "abb" {
+		t.Fatalf("expected abb to be kept as ab, got %v", r.SortedIDs())
+	}
+	if !reflect.DeepEqual(g.TransitiveReduction().SortedIDs(), g.SortedIDs()) {
+		t.Fatalf("expected sorted value %v != %v", g.SortedIDs(), g.TransitiveReduction().SortedIDs())
+	}
+}
@@ -0,0 +1,65 @@
+package toposort
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PrintTree writes the graph to w as an indented tree, starting from each
+// root, with the keys depending on a key listed below it:
+//
+//	Jonas
+//	`-- Sophie
+//	    |-- Nick
+//	    `-- Barbara
+//
+// A key depending on several keys is expanded only once, and marked with (*)
+// where it appears again.
+func (g *Graph[K]) PrintTree(w io.Writer) error {
+	_, err := io.WriteString(w, g.String())
+	return err
+}
+
+// String returns the graph as an indented tree, as printed by PrintTree.
+func (g *Graph[K]) String() string {
+	type item struct {
+		v      int
+		prefix string // indentation of the children of v
+		line   string // branch drawn before v
+	}
+
+	var b strings.Builder
+
+	printed := make([]bool, len(g.ids))
+	stack := []item{}
+	for i := len(g.sorted) - 1; i >= 0; i-- {
+		if v := g.sorted[i]; len(g.befores[v]) == 0 {
+			stack = append(stack, item{v: v})
+		}
+	}
+
+	for len(stack) > 0 {
+		it := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		fmt.Fprintf(&b, "%s%v", it.line, g.ids[it.v])
+		if printed[it.v] {
+			b.WriteString(" (*)\n")
+			continue
+		}
+		b.WriteString("\n")
+		printed[it.v] = true
+
+		afters := g.afters[it.v]
+		for i := len(afters) - 1; i >= 0; i-- {
+			line, prefix := it.prefix+"|-- ", it.prefix+"|   "
+			if i == len(afters)-1 {
+				line, prefix = it.prefix+"`-- ", it.prefix+"    "
+			}
+			stack = append(stack, item{v: afters[i], prefix: prefix, line: line})
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package toposort_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/onur1/toposort"
+)
+
+func TestGraphPrintTree(t *testing.T) {
+	g, err := toposort.ReadEdges(strings.NewReader(`Jonas Sophie
+Sophie Nick
+Sophie Barbara
+Nick Ruby
+Barbara Ruby
+Daniel Ruby
+`), toposort.FormatPairs, toposort.WithAllowMultipleRoots(), toposort.WithLexicographicOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Daniel\n" +
+		"`-- Ruby\n" +
+		"Jonas\n" +
+		"`-- Sophie\n" +
+		"    |-- Nick\n" +
+		"    |   `-- Ruby (*)\n" +
+		"    `-- Barbara\n" +
+		"        `-- Ruby (*)\n"
+
+	var b bytes.Buffer
+	if err := g.PrintTree(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Fatalf("expected tree %q != %q", expected, b.String())
+	}
+	if s := g.String(); s != expected {
+		t.Fatalf("expected tree %q != %q", expected, s)
+	}
+}